@@ -0,0 +1,157 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"golang.org/x/exp/slices"
+)
+
+// SyncAliasesOptions configures the SyncAliases operation.
+type SyncAliasesOptions struct {
+	// AdvisoryDocs is the Index of advisory documents on which to operate.
+	AdvisoryDocs *configs.Index[v2.Document]
+
+	// AliasFinder is used to look up the aliases of each advisory.
+	AliasFinder vuln.AliasFinder
+
+	// SelectedPackages is a list of packages whose advisories should be synced. If
+	// empty, all packages' advisories are synced.
+	SelectedPackages []string
+}
+
+// SyncAliases adds any aliases known to the AliasFinder to the existing
+// advisories in the index. Existing aliases are never removed.
+func SyncAliases(opts SyncAliasesOptions) error {
+	if opts.AliasFinder == nil {
+		return fmt.Errorf("an alias finder must be specified")
+	}
+
+	selections := []configs.Selection[v2.Document]{opts.AdvisoryDocs.Select()}
+	if len(opts.SelectedPackages) > 0 {
+		selections = nil
+		for _, pkg := range opts.SelectedPackages {
+			selections = append(selections, opts.AdvisoryDocs.Select().WhereName(pkg))
+		}
+	}
+
+	u := v2.NewAdvisoriesSectionUpdater(func(doc v2.Document) (v2.Advisories, error) {
+		advisories := doc.Advisories
+		changed := false
+
+		for _, adv := range doc.Advisories {
+			aliases := resolveAliases(adv.ID, adv.Aliases, opts.AliasFinder)
+			if sameAliases(aliases, adv.Aliases) {
+				continue
+			}
+
+			adv.Aliases = aliases
+			advisories = advisories.Update(adv.ID, adv)
+			changed = true
+		}
+
+		if !changed {
+			// Avoid rewriting documents that don't need any updates.
+			return nil, configs.ErrSkip
+		}
+
+		return advisories, nil
+	})
+
+	for _, selection := range selections {
+		if err := selection.Update(u); err != nil {
+			return fmt.Errorf("unable to sync aliases: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resolveAliases returns the given aliases merged with any further aliases
+// known to the finder for the given ID and for each of the given aliases. Only
+// valid vulnerability IDs are included, and the ID itself is excluded.
+func resolveAliases(id string, aliases []string, finder vuln.AliasFinder) []string {
+	if finder == nil {
+		return aliases
+	}
+
+	found := finder.Aliases(id)
+	for _, alias := range aliases {
+		found = append(found, finder.Aliases(alias)...)
+	}
+
+	return mergeAliases(id, aliases, found)
+}
+
+// mergeAliases returns the union of existing and additional aliases, sorted
+// and without duplicates. The given ID and any invalid vulnerability IDs in
+// additional are excluded.
+func mergeAliases(id string, existing, additional []string) []string {
+	seen := map[string]struct{}{id: {}}
+	var merged []string
+
+	for _, alias := range existing {
+		if _, ok := seen[alias]; ok {
+			continue
+		}
+		seen[alias] = struct{}{}
+		merged = append(merged, alias)
+	}
+
+	for _, alias := range additional {
+		if _, ok := seen[alias]; ok {
+			continue
+		}
+		if vuln.ValidateID(alias) != nil {
+			continue
+		}
+		seen[alias] = struct{}{}
+		merged = append(merged, alias)
+	}
+
+	sort.Strings(merged)
+	return merged
+}
+
+// sameAliases reports whether a and b hold the same aliases, in any order.
+func sameAliases(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+
+	return slices.Equal(a, b)
+}
+
+// addAliases adds the given aliases to the advisory with the given ID in the
+// selected documents.
+func addAliases(documents configs.Selection[v2.Document], advisoryID string, aliases []string) error {
+	u := v2.NewAdvisoriesSectionUpdater(func(doc v2.Document) (v2.Advisories, error) {
+		advisories := doc.Advisories
+
+		adv, ok := advisories.Get(advisoryID)
+		if !ok {
+			return nil, fmt.Errorf("advisory %q does not exist", advisoryID)
+		}
+
+		adv.Aliases = mergeAliases(adv.ID, adv.Aliases, aliases)
+		return advisories.Update(advisoryID, adv), nil
+	})
+
+	return documents.Update(u)
+}
+
+// findAdvisoryByAnyID returns the first advisory that refers to the given
+// vulnerability ID or to any of the given aliases, either by its own ID or by
+// one of its aliases.
+func findAdvisoryByAnyID(advisories v2.Advisories, id string, aliases []string) (v2.Advisory, bool) {
+	for _, candidate := range append([]string{id}, aliases...) {
+		if adv, ok := advisories.GetByVulnerability(candidate); ok {
+			return adv, true
+		}
+	}
+
+	return v2.Advisory{}, false
+}
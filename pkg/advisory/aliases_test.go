@@ -0,0 +1,60 @@
+package advisory
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os/memfs"
+)
+
+// staticAliasFinder is a vuln.AliasFinder backed by a fixed map, for testing.
+type staticAliasFinder map[string][]string
+
+func (f staticAliasFinder) Aliases(id string) []string {
+	return f[id]
+}
+
+func TestSyncAliases(t *testing.T) {
+	finder := staticAliasFinder{
+		"CVE-2020-8927":       {"GHSA-5v8v-66v8-mwm7", "EXAMPLE-2020-29"},
+		"GHSA-2qjp-425j-52j9": {"CVE-2023-1234", "GO-2023-2095"},
+		"CVE-2023-5678":       {"GO-2023-1882"},
+	}
+
+	fsys := memfs.New(os.DirFS("testdata/aliases/advisories"))
+	advisoryDocs, err := v2.NewIndex(fsys)
+	require.NoError(t, err)
+
+	err = SyncAliases(SyncAliasesOptions{
+		AdvisoryDocs: advisoryDocs,
+		AliasFinder:  finder,
+	})
+	require.NoError(t, err)
+
+	doc := advisoryDocs.Select().WhereName("brotli").Configurations()[0]
+
+	expected := map[string][]string{
+		// The EXAMPLE ID isn't in a known vulnerability ID namespace, so it's not
+		// recorded.
+		"CVE-2020-8927": {"GHSA-5v8v-66v8-mwm7"},
+
+		// Aliases of existing aliases are included, too.
+		"CVE-2023-1234": {"GHSA-2qjp-425j-52j9", "GO-2023-2095"},
+
+		// The duplicate alias is dropped even though the number of aliases
+		// stays the same.
+		"CVE-2023-5678": {"GHSA-3vm4-22fp-5rfm", "GO-2023-1882"},
+	}
+
+	for id, expectedAliases := range expected {
+		adv, ok := doc.Advisories.Get(id)
+		require.True(t, ok)
+
+		if diff := cmp.Diff(expectedAliases, adv.Aliases); diff != "" {
+			t.Errorf("SyncAliases() aliases for %s mismatch (-want +got):\n%s", id, diff)
+		}
+	}
+}
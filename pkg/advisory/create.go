@@ -6,22 +6,36 @@ import (
 
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
 )
 
 // CreateOptions configures the Create operation.
 type CreateOptions struct {
 	// AdvisoryDocs is the Index of advisory documents on which to operate.
 	AdvisoryDocs *configs.Index[v2.Document]
+
+	// AliasFinder is used to find existing advisories that refer to the requested
+	// vulnerability by a different ID. If nil, only the request's own ID and
+	// aliases are considered.
+	AliasFinder vuln.AliasFinder
 }
 
 // Create creates a new advisory in the `advisories` section of the document at
-// the provided path.
+// the provided path. The advisory's ID is the most preferred of the requested
+// ID and its aliases (see vuln.SelectPrimaryID). If the package already has an
+// advisory for the same vulnerability under a different ID (i.e. an alias), the
+// request's event is appended to that advisory instead, and the requested ID is
+// recorded as an alias.
 func Create(req Request, opts CreateOptions) error {
 	err := req.Validate()
 	if err != nil {
 		return err
 	}
 
+	requestedID := req.VulnerabilityID
+	req.Aliases = resolveAliases(req.VulnerabilityID, req.Aliases, opts.AliasFinder)
+	req.VulnerabilityID, req.Aliases = vuln.SelectPrimaryID(req.VulnerabilityID, req.Aliases)
+
 	documents := opts.AdvisoryDocs.Select().WhereName(req.Package)
 	count := documents.Len()
 
@@ -35,11 +49,20 @@ func Create(req Request, opts CreateOptions) error {
 
 		// i.e. exactly one advisories file for this package
 		u := v2.NewAdvisoriesSectionUpdater(func(doc v2.Document) (v2.Advisories, error) {
-			if _, exists := doc.Advisories.Get(newAdvisoryID); exists {
-				return v2.Advisories{}, fmt.Errorf("advisory %q already exists for %q", newAdvisoryID, req.Package)
+			if _, exists := doc.Advisories.Get(requestedID); exists {
+				return v2.Advisories{}, fmt.Errorf("advisory %q already exists for %q", requestedID, req.Package)
 			}
 
 			advisories := doc.Advisories
+
+			if existing, exists := findAdvisoryByAnyID(advisories, newAdvisoryID, req.Aliases); exists {
+				existing.Aliases = mergeAliases(existing.ID, existing.Aliases, append([]string{newAdvisoryID}, req.Aliases...))
+				existing.Events = append(existing.Events, req.Event)
+				advisories = advisories.Update(existing.ID, existing)
+
+				return advisories, nil
+			}
+
 			newAdvisory := v2.Advisory{
 				ID:      newAdvisoryID,
 				Aliases: req.Aliases,
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
 	"github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os/memfs"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
 )
 
 func TestCreate(t *testing.T) {
@@ -18,6 +19,7 @@ func TestCreate(t *testing.T) {
 	tests := []struct {
 		name        string
 		req         Request
+		aliasFinder vuln.AliasFinder
 		wantErr     bool
 		expectedDoc v2.Document
 	}{
@@ -159,6 +161,87 @@ func TestCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "new advisory uses the preferred ID",
+			req: Request{
+				Package:         "crane",
+				VulnerabilityID: "GHSA-5v8v-66v8-mwm7",
+				Event: v2.Event{
+					Timestamp: testTime,
+					Type:      v2.EventTypeDetection,
+					Data: v2.Detection{
+						Type: v2.DetectionTypeManual,
+					},
+				},
+			},
+			aliasFinder: staticAliasFinder{
+				"GHSA-5v8v-66v8-mwm7": {"CVE-2023-1234"},
+			},
+			wantErr: false,
+			expectedDoc: v2.Document{
+				SchemaVersion: v2.SchemaVersion,
+				Package:       v2.Package{Name: "crane"},
+				Advisories: v2.Advisories{
+					{
+						ID:      "CVE-2023-1234",
+						Aliases: []string{"GHSA-5v8v-66v8-mwm7"},
+						Events: []v2.Event{
+							{
+								Timestamp: testTime,
+								Type:      v2.EventTypeDetection,
+								Data: v2.Detection{
+									Type: v2.DetectionTypeManual,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "vulnerability already tracked under an alias",
+			req: Request{
+				Package:         "brotli",
+				VulnerabilityID: "GHSA-5v8v-66v8-mwm7",
+				Event: v2.Event{
+					Timestamp: testTime,
+					Type:      v2.EventTypeDetection,
+					Data: v2.Detection{
+						Type: v2.DetectionTypeManual,
+					},
+				},
+			},
+			aliasFinder: staticAliasFinder{
+				"GHSA-5v8v-66v8-mwm7": {"CVE-2020-8927"},
+			},
+			wantErr: false,
+			expectedDoc: v2.Document{
+				SchemaVersion: v2.SchemaVersion,
+				Package:       v2.Package{Name: "brotli"},
+				Advisories: v2.Advisories{
+					{
+						ID:      "CVE-2020-8927",
+						Aliases: []string{"GHSA-5v8v-66v8-mwm7"},
+						Events: []v2.Event{
+							{
+								Timestamp: brotliExistingEventTime,
+								Type:      v2.EventTypeFixed,
+								Data: v2.Fixed{
+									FixedVersion: "1.0.9-r0",
+								},
+							},
+							{
+								Timestamp: testTime,
+								Type:      v2.EventTypeDetection,
+								Data: v2.Detection{
+									Type: v2.DetectionTypeManual,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "no events",
 			req: Request{
@@ -195,6 +278,7 @@ func TestCreate(t *testing.T) {
 
 			err = Create(tt.req, CreateOptions{
 				AdvisoryDocs: advisoryDocs,
+				AliasFinder:  tt.aliasFinder,
 			})
 
 			if (err != nil) != tt.wantErr {
@@ -37,6 +37,11 @@ type DiscoverOptions struct {
 
 	// VulnEvents is a channel of events that occur during vulnerability discovery.
 	VulnEvents chan<- interface{}
+
+	// AliasFinder is used to recognize discovered vulnerabilities that already
+	// have an advisory under a different ID, and to populate the aliases of new
+	// advisories. If nil, vulnerabilities are only matched by their exact ID.
+	AliasFinder vuln.AliasFinder
 }
 
 // Discover searches for new vulnerabilities that match packages in a config
@@ -95,7 +100,10 @@ func (opts DiscoverOptions) discoverMatchesForPackage(ctx context.Context, pkg s
 		opts.VulnEvents <- vuln.EventPackageMatchingError{Package: pkg, Err: err}
 	}
 
-	matches = opts.filterMatchesForPackage(pkg, matches)
+	matches, aliasUpdates := opts.filterMatchesForPackage(pkg, matches)
+	if err := opts.recordAliasesForPackage(pkg, aliasUpdates); err != nil {
+		return err
+	}
 
 	opts.VulnEvents <- vuln.EventPackageMatchingFinished{Package: pkg, Matches: matches}
 
@@ -106,7 +114,10 @@ func (opts DiscoverOptions) discoverMatchesForPackage(ctx context.Context, pkg s
 			VulnerabilityID: match.Vulnerability.ID,
 			Aliases:         nil,
 			Event:           advisoryEventForNewDiscovery(match),
-		}, CreateOptions{opts.AdvisoryDocs})
+		}, CreateOptions{
+			AdvisoryDocs: opts.AdvisoryDocs,
+			AliasFinder:  opts.AliasFinder,
+		})
 		if err != nil {
 			return err
 		}
@@ -115,11 +126,16 @@ func (opts DiscoverOptions) discoverMatchesForPackage(ctx context.Context, pkg s
 	return nil
 }
 
-func (opts DiscoverOptions) filterMatchesForPackage(pkg string, matches []vuln.Match) []vuln.Match {
+// filterMatchesForPackage returns the matches that need a new advisory for the
+// package. Matches for vulnerabilities already tracked under another ID are
+// left out, and are instead returned as the aliases to add to those advisories,
+// by advisory ID.
+func (opts DiscoverOptions) filterMatchesForPackage(pkg string, matches []vuln.Match) ([]vuln.Match, map[string][]string) {
 	buildCfgEntry, _ := opts.BuildCfgs.Select().WhereName(pkg).First() //nolint:errcheck
 	buildCfg := buildCfgEntry.Configuration()
 
 	var filteredMatches []vuln.Match
+	aliasUpdates := make(map[string][]string)
 
 	for i := range matches {
 		match := matches[i]
@@ -147,10 +163,34 @@ func (opts DiscoverOptions) filterMatchesForPackage(pkg string, matches []vuln.M
 			continue
 		}
 
+		aliases := resolveAliases(vulnID, nil, opts.AliasFinder)
+		if existing, exists := findAdvisoryByAnyID(document.Advisories, vulnID, aliases); exists {
+			// The vulnerability is already tracked under a different ID. Rather than
+			// creating a duplicate advisory, record the discovered ID as an alias.
+			aliasUpdates[existing.ID] = append(aliasUpdates[existing.ID], append([]string{vulnID}, aliases...)...)
+			continue
+		}
+
 		filteredMatches = append(filteredMatches, match)
 	}
 
-	return filteredMatches
+	return filteredMatches, aliasUpdates
+}
+
+// recordAliasesForPackage adds the given aliases, by advisory ID, to the
+// package's existing advisories.
+func (opts DiscoverOptions) recordAliasesForPackage(pkg string, aliasUpdates map[string][]string) error {
+	advisoryDocuments := opts.AdvisoryDocs.Select().WhereName(pkg)
+
+	ids := lo.Keys(aliasUpdates)
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := addAliases(advisoryDocuments, id, aliasUpdates[id]); err != nil {
+			return fmt.Errorf("unable to add aliases %q to advisory %q for %q: %w", aliasUpdates[id], id, pkg, err)
+		}
+	}
+
+	return nil
 }
 
 func advisoryEventForNewDiscovery(match vuln.Match) v2.Event {
@@ -0,0 +1,148 @@
+package buildenv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the per-repo config file, which is read from
+// the root of the repo that holds the package configurations. It starts with a
+// dot so that it's not mistaken for a package configuration.
+const ConfigFileName = ".wolfictl.yaml"
+
+// Env is the environment that packages are built in, i.e. the settings for
+// melange that aren't part of the packages' own configurations.
+type Env struct {
+	// Keyring lists the keys (paths or URLs) of the repositories that build
+	// dependencies are installed from.
+	Keyring []string `yaml:"keyring,omitempty"`
+
+	// Repositories lists the repositories (paths or URLs) that build
+	// dependencies are installed from, in addition to the locally built
+	// packages.
+	Repositories []string `yaml:"repositories,omitempty"`
+
+	// SigningKey is the path of the private key that built packages are signed
+	// with. A relative path is relative to the directory of the package
+	// configurations. See ResolvePaths.
+	SigningKey string `yaml:"signing-key,omitempty"`
+
+	// Namespace is the namespace of the built packages, used in their package
+	// URLs (e.g. "wolfi").
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// CacheSource is the location of a cache of package sources, which is used
+	// to populate CacheDir (e.g. "gs://wolfi-sources/").
+	CacheSource string `yaml:"cache-source,omitempty"`
+
+	// CacheDir is the directory where package sources are cached between
+	// builds. A relative path is relative to the directory of the package
+	// configurations, like SigningKey's.
+	CacheDir string `yaml:"cache-dir,omitempty"`
+}
+
+// Default returns the Env to use if nothing else is configured: the signing key
+// and cache directory that have always been used locally, and the settings of
+// the given distro.
+func Default(d distro.Distro) Env {
+	env := Env{
+		SigningKey:  "local-melange.rsa",
+		Namespace:   strings.ToLower(d.Name),
+		CacheSource: d.SourceCacheURL,
+		CacheDir:    "./melange-cache/",
+	}
+
+	if d.APKRepositoryURL != "" {
+		env.Repositories = []string{d.APKRepositoryURL}
+	}
+	if d.APKRepositoryKeyURL != "" {
+		env.Keyring = []string{d.APKRepositoryKeyURL}
+	}
+
+	return env
+}
+
+// Merge returns a copy of e, with each of its settings replaced by the
+// corresponding setting of override, if that's set.
+func (e Env) Merge(override Env) Env {
+	if override.Keyring != nil {
+		e.Keyring = override.Keyring
+	}
+	if override.Repositories != nil {
+		e.Repositories = override.Repositories
+	}
+	if override.SigningKey != "" {
+		e.SigningKey = override.SigningKey
+	}
+	if override.Namespace != "" {
+		e.Namespace = override.Namespace
+	}
+	if override.CacheSource != "" {
+		e.CacheSource = override.CacheSource
+	}
+	if override.CacheDir != "" {
+		e.CacheDir = override.CacheDir
+	}
+
+	return e
+}
+
+// ResolvePaths returns a copy of e, with its relative SigningKey and CacheDir
+// made relative to dir, the directory of the package configurations, instead
+// of to the current working directory.
+func (e Env) ResolvePaths(dir string) Env {
+	if e.SigningKey != "" && !filepath.IsAbs(e.SigningKey) {
+		e.SigningKey = filepath.Join(dir, e.SigningKey)
+	}
+	if e.CacheDir != "" && !filepath.IsAbs(e.CacheDir) {
+		e.CacheDir = filepath.Join(dir, e.CacheDir)
+	}
+
+	return e
+}
+
+// configFile is the structure of the per-repo config file. The build
+// environment is in its own section, so that settings for other commands can be
+// added later.
+type configFile struct {
+	Build Env `yaml:"build"`
+}
+
+// Load reads the build environment from the config file at the given path. If
+// the file doesn't exist, Load returns an empty Env.
+func Load(path string) (Env, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Env{}, nil
+	}
+	if err != nil {
+		return Env{}, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	env, err := decode(f)
+	if err != nil {
+		return Env{}, fmt.Errorf("failed to decode config file %q: %w", path, err)
+	}
+
+	return env, nil
+}
+
+func decode(r io.Reader) (Env, error) {
+	cfg := configFile{}
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Env{}, err
+	}
+
+	return cfg.Build, nil
+}
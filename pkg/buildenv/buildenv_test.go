@@ -0,0 +1,112 @@
+package buildenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func TestDefault(t *testing.T) {
+	wolfi, err := distro.ByName("Wolfi")
+	require.NoError(t, err)
+
+	assert.Equal(t, Env{
+		Keyring:      []string{"https://packages.wolfi.dev/os/wolfi-signing.rsa.pub"},
+		Repositories: []string{"https://packages.wolfi.dev/os"},
+		SigningKey:   "local-melange.rsa",
+		Namespace:    "wolfi",
+		CacheSource:  "gs://wolfi-sources/",
+		CacheDir:     "./melange-cache/",
+	}, Default(wolfi))
+
+	chainguard, err := distro.ByName("Chainguard")
+	require.NoError(t, err)
+
+	env := Default(chainguard)
+	assert.Equal(t, []string{"https://packages.cgr.dev/os"}, env.Repositories)
+	assert.Equal(t, "chainguard", env.Namespace)
+	assert.Empty(t, env.CacheSource)
+}
+
+func TestMerge(t *testing.T) {
+	base := Env{
+		Keyring:      []string{"a.rsa.pub"},
+		Repositories: []string{"https://a.example.com/os"},
+		SigningKey:   "a.rsa",
+		Namespace:    "a",
+		CacheSource:  "gs://a/",
+		CacheDir:     "./a-cache/",
+	}
+
+	assert.Equal(t, base, base.Merge(Env{}))
+
+	merged := base.Merge(Env{
+		Repositories: []string{"https://b.example.com/os"},
+		Namespace:    "b",
+	})
+	assert.Equal(t, Env{
+		Keyring:      []string{"a.rsa.pub"},
+		Repositories: []string{"https://b.example.com/os"},
+		SigningKey:   "a.rsa",
+		Namespace:    "b",
+		CacheSource:  "gs://a/",
+		CacheDir:     "./a-cache/",
+	}, merged)
+
+	// An explicitly empty list replaces the base list.
+	assert.Empty(t, base.Merge(Env{Keyring: []string{}}).Keyring)
+}
+
+func TestResolvePaths(t *testing.T) {
+	env := Env{
+		SigningKey: "local-melange.rsa",
+		CacheDir:   "./melange-cache/",
+		Namespace:  "wolfi",
+	}
+
+	assert.Equal(t, Env{
+		SigningKey: filepath.Join("os", "local-melange.rsa"),
+		CacheDir:   filepath.Join("os", "melange-cache"),
+		Namespace:  "wolfi",
+	}, env.ResolvePaths("os"))
+
+	abs := Env{SigningKey: "/keys/a.rsa", CacheDir: "/var/cache/melange"}
+	assert.Equal(t, abs, abs.ResolvePaths("os"))
+
+	assert.Equal(t, Env{}, Env{}.ResolvePaths("os"))
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("config file", func(t *testing.T) {
+		env, err := Load(filepath.Join("testdata", ConfigFileName))
+		require.NoError(t, err)
+
+		assert.Equal(t, Env{
+			Keyring:      []string{"https://packages.example.com/os/example.rsa.pub"},
+			Repositories: []string{"https://packages.example.com/os"},
+			SigningKey:   "example.rsa",
+			Namespace:    "example",
+			CacheDir:     "/var/cache/melange",
+		}, env)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		env, err := Load(filepath.Join(t.TempDir(), ConfigFileName))
+		require.NoError(t, err)
+		assert.Equal(t, Env{}, env)
+	})
+
+	t.Run("unknown setting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ConfigFileName)
+		require.NoError(t, os.WriteFile(path, []byte("build:\n  signing-kye: example.rsa\n"), 0o600))
+
+		_, err := Load(path)
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "signing-kye"))
+	})
+}
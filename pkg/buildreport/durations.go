@@ -0,0 +1,118 @@
+package buildreport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultEstimate is how long a build is expected to take when there's no
+// history of builds to go by.
+const DefaultEstimate = time.Minute
+
+// Durations is a history of how long packages took to build, in seconds, by
+// architecture and then by package name. It's kept between builds, so that
+// later builds can prioritize the packages that hold up the most work, and
+// estimate how long they'll take.
+type Durations map[string]map[string]float64
+
+// LoadDurations reads the Durations from the JSON file at the given path. If the
+// file doesn't exist, LoadDurations returns an empty Durations.
+func LoadDurations(path string) (Durations, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Durations{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build durations: %w", err)
+	}
+
+	d := Durations{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, fmt.Errorf("failed to decode build durations %q: %w", path, err)
+	}
+
+	return d, nil
+}
+
+// Save writes the Durations to the given path as JSON, creating its directory
+// if needed.
+func (d Durations) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for build durations: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create build durations file: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("failed to encode build durations: %w", err)
+	}
+
+	return nil
+}
+
+// Record adds the durations of the builds in the Report, replacing any earlier
+// durations of the same packages. Only builds that succeeded are recorded, since
+// a failed build can stop at any point.
+func (d Durations) Record(r *Report) {
+	for _, p := range r.Packages {
+		for _, a := range p.Archs {
+			if a.Status != StatusBuilt {
+				continue
+			}
+			if d[a.Arch] == nil {
+				d[a.Arch] = map[string]float64{}
+			}
+			d[a.Arch][p.Name] = a.DurationSeconds
+		}
+	}
+}
+
+// Estimate returns how long the package is expected to take to build for the
+// architecture. Without a history of the package for that architecture, it
+// falls back to the package's duration for another architecture, then to the
+// median of every recorded duration for the architecture, and then to
+// DefaultEstimate.
+func (d Durations) Estimate(arch, pkg string) time.Duration {
+	if s, ok := d[arch][pkg]; ok {
+		return seconds(s)
+	}
+
+	// Prefer the slowest other architecture, to err on the side of priority.
+	var other []float64
+	for a, byPkg := range d {
+		if s, ok := byPkg[pkg]; ok && a != arch {
+			other = append(other, s)
+		}
+	}
+	if len(other) > 0 {
+		sort.Float64s(other)
+		return seconds(other[len(other)-1])
+	}
+
+	var all []float64
+	for _, s := range d[arch] {
+		all = append(all, s)
+	}
+	if len(all) > 0 {
+		sort.Float64s(all)
+		return seconds(all[len(all)/2])
+	}
+
+	return DefaultEstimate
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
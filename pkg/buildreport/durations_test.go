@@ -0,0 +1,55 @@
+package buildreport
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages", "build-durations.json")
+
+	d, err := LoadDurations(path)
+	require.NoError(t, err)
+	assert.Empty(t, d)
+	assert.Equal(t, DefaultEstimate, d.Estimate("x86_64", "gcc"))
+
+	d.Record(&Report{Packages: []Package{
+		{Name: "gcc", Archs: []Arch{
+			{Arch: "x86_64", Status: StatusBuilt, DurationSeconds: 3600},
+			{Arch: "aarch64", Status: StatusFailed, DurationSeconds: 10},
+		}},
+		{Name: "glibc", Archs: []Arch{
+			{Arch: "x86_64", Status: StatusBuilt, DurationSeconds: 1200},
+			{Arch: "aarch64", Status: StatusBuilt, DurationSeconds: 1800},
+		}},
+		{Name: "zlib", Archs: []Arch{
+			{Arch: "x86_64", Status: StatusBuilt, DurationSeconds: 30},
+			{Arch: "aarch64", Status: StatusSkippedExisting},
+		}},
+	}})
+	require.NoError(t, d.Save(path))
+
+	loaded, err := LoadDurations(path)
+	require.NoError(t, err)
+	assert.Equal(t, d, loaded)
+
+	cases := []struct {
+		arch, pkg string
+		expected  time.Duration
+	}{
+		{"x86_64", "gcc", time.Hour},
+		{"aarch64", "glibc", 30 * time.Minute},
+		// Failed builds aren't recorded, so gcc's x86_64 duration is used.
+		{"aarch64", "gcc", time.Hour},
+		// An unknown package is expected to take the median time for the arch.
+		{"x86_64", "openssl", 20 * time.Minute},
+		{"riscv64", "openssl", DefaultEstimate},
+	}
+	for _, tt := range cases {
+		assert.Equal(t, tt.expected, loaded.Estimate(tt.arch, tt.pkg), "%s for %s", tt.pkg, tt.arch)
+	}
+}
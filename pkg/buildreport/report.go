@@ -0,0 +1,191 @@
+package buildreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// Status is the outcome of building a package, or of building a package for one
+// architecture.
+type Status string
+
+const (
+	// StatusBuilt means the package was built.
+	StatusBuilt Status = "built"
+
+	// StatusSkippedExisting means the package wasn't built because it had
+	// already been built.
+	StatusSkippedExisting Status = "skipped-existing"
+
+	// StatusDryRun means the package would have been built, but this was a dry
+	// run.
+	StatusDryRun Status = "dry-run"
+
+	// StatusFailed means the package failed to build.
+	StatusFailed Status = "failed"
+
+	// StatusBlocked means the package wasn't built because one of its
+	// dependencies failed to build, or was itself blocked.
+	StatusBlocked Status = "blocked"
+
+	// StatusUnsupported means the package wasn't built because it doesn't
+	// support the architecture, per its target architectures.
+	StatusUnsupported Status = "unsupported"
+)
+
+// Statuses lists every Status, in the order they're presented in reports.
+var Statuses = []Status{StatusBuilt, StatusSkippedExisting, StatusDryRun, StatusFailed, StatusBlocked, StatusUnsupported}
+
+// Report describes the outcome of a build of many packages.
+type Report struct {
+	// Started is when the build started.
+	Started time.Time `json:"started"`
+
+	// Finished is when the build finished.
+	Finished time.Time `json:"finished"`
+
+	// Packages holds the outcome for each package, sorted by name.
+	Packages []Package `json:"packages"`
+}
+
+// Package describes the outcome of building a package.
+type Package struct {
+	// Name is the name of the package's origin (i.e. the name of its
+	// configuration).
+	Name string `json:"name"`
+
+	// Version is the package's full version, including the epoch (e.g.
+	// "1.2.3-r0"), if its configuration could be parsed.
+	Version string `json:"version,omitempty"`
+
+	// Status is the outcome for the package as a whole. See PackageStatus.
+	Status Status `json:"status"`
+
+	// BlockedBy lists the dependencies that failed or were blocked, for any
+	// architecture, if the package is blocked.
+	BlockedBy []string `json:"blockedBy,omitempty"`
+
+	// Archs holds the outcome for each architecture.
+	Archs []Arch `json:"archs,omitempty"`
+}
+
+// NewPackage returns the outcome of building a package, given the outcome for
+// each of its architectures.
+func NewPackage(name, version string, archs []Arch) Package {
+	p := Package{
+		Name:    name,
+		Version: version,
+		Status:  PackageStatus(archs),
+		Archs:   archs,
+	}
+
+	for _, a := range archs {
+		for _, dep := range a.BlockedBy {
+			if !slices.Contains(p.BlockedBy, dep) {
+				p.BlockedBy = append(p.BlockedBy, dep)
+			}
+		}
+	}
+	slices.Sort(p.BlockedBy)
+
+	return p
+}
+
+// Arch describes the outcome of building a package for one architecture.
+type Arch struct {
+	// Arch is the architecture, e.g. "x86_64".
+	Arch string `json:"arch"`
+
+	// Status is the outcome for this architecture.
+	Status Status `json:"status"`
+
+	// DurationSeconds is how long the build took, in seconds.
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+
+	// LogPath is the path of the build log, if one was written.
+	LogPath string `json:"logPath,omitempty"`
+
+	// Error describes why the build failed, if it failed.
+	Error string `json:"error,omitempty"`
+
+	// BlockedBy lists the dependencies that failed or were blocked for this
+	// architecture, if the build is blocked.
+	BlockedBy []string `json:"blockedBy,omitempty"`
+}
+
+// Duration returns how long the build took.
+func (a Arch) Duration() time.Duration {
+	return seconds(a.DurationSeconds)
+}
+
+// PackageStatus returns the outcome for a package as a whole, given the outcome
+// for each of its architectures: failed if any architecture failed, or else
+// blocked if any architecture was blocked, or else built if any architecture was
+// built, and so on. A package with no supported architectures is unsupported.
+func PackageStatus(archs []Arch) Status {
+	seen := map[Status]bool{}
+	for _, a := range archs {
+		seen[a.Status] = true
+	}
+
+	for _, s := range []Status{StatusFailed, StatusBlocked, StatusBuilt, StatusDryRun, StatusSkippedExisting} {
+		if seen[s] {
+			return s
+		}
+	}
+
+	return StatusUnsupported
+}
+
+// New returns a Report of the given packages, sorted by name.
+func New(started, finished time.Time, packages []Package) *Report {
+	sorted := make([]Package, len(packages))
+	copy(sorted, packages)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return &Report{
+		Started:  started,
+		Finished: finished,
+		Packages: sorted,
+	}
+}
+
+// Counts returns the number of packages with each Status.
+func (r Report) Counts() map[Status]int {
+	counts := make(map[Status]int)
+	for _, p := range r.Packages {
+		counts[p.Status]++
+	}
+
+	return counts
+}
+
+// Failed returns the names of the packages that failed to build.
+func (r Report) Failed() []string {
+	var names []string
+	for _, p := range r.Packages {
+		if p.Status == StatusFailed {
+			names = append(names, p.Name)
+		}
+	}
+
+	return names
+}
+
+// Encode writes the Report to w as JSON.
+func (r Report) Encode(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode build report: %w", err)
+	}
+
+	return nil
+}
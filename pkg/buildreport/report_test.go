@@ -0,0 +1,86 @@
+package buildreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []Status
+		expected Status
+	}{
+		{"no archs", nil, StatusUnsupported},
+		{"all unsupported", []Status{StatusUnsupported, StatusUnsupported}, StatusUnsupported},
+		{"all skipped", []Status{StatusSkippedExisting, StatusSkippedExisting}, StatusSkippedExisting},
+		{"skipped and unsupported", []Status{StatusSkippedExisting, StatusUnsupported}, StatusSkippedExisting},
+		{"some built", []Status{StatusSkippedExisting, StatusBuilt}, StatusBuilt},
+		{"one failed", []Status{StatusBuilt, StatusFailed}, StatusFailed},
+		{"one blocked", []Status{StatusBuilt, StatusBlocked}, StatusBlocked},
+		{"failed and blocked", []Status{StatusBlocked, StatusFailed}, StatusFailed},
+		{"dry run", []Status{StatusDryRun, StatusSkippedExisting}, StatusDryRun},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var archs []Arch
+			for _, s := range tt.statuses {
+				archs = append(archs, Arch{Status: s})
+			}
+			assert.Equal(t, tt.expected, PackageStatus(archs))
+		})
+	}
+}
+
+func TestNewPackage(t *testing.T) {
+	p := NewPackage("curl", "8.4.0-r0", []Arch{
+		{Arch: "x86_64", Status: StatusBlocked, BlockedBy: []string{"openssl", "nghttp2"}},
+		{Arch: "aarch64", Status: StatusBlocked, BlockedBy: []string{"openssl"}},
+	})
+
+	assert.Equal(t, StatusBlocked, p.Status)
+	assert.Equal(t, []string{"nghttp2", "openssl"}, p.BlockedBy)
+	assert.Len(t, p.Archs, 2)
+}
+
+func TestReport(t *testing.T) {
+	started := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	report := New(started, started.Add(time.Hour), []Package{
+		{Name: "curl", Status: StatusBlocked, BlockedBy: []string{"openssl"}},
+		{Name: "openssl", Version: "3.1.3-r0", Status: StatusFailed, Archs: []Arch{
+			{Arch: "x86_64", Status: StatusFailed, DurationSeconds: 90, Error: "exit status 2"},
+		}},
+		{Name: "zlib", Version: "1.3-r0", Status: StatusBuilt, Archs: []Arch{
+			{Arch: "x86_64", Status: StatusBuilt, DurationSeconds: 12.5, LogPath: "packages/x86_64/buildlogs/zlib-1.3-r0.apk.log"},
+		}},
+		{Name: "bash", Version: "5.2-r0", Status: StatusSkippedExisting},
+	})
+
+	var names []string
+	for _, p := range report.Packages {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"bash", "curl", "openssl", "zlib"}, names)
+
+	assert.Equal(t, map[Status]int{
+		StatusBuilt:           1,
+		StatusSkippedExisting: 1,
+		StatusFailed:          1,
+		StatusBlocked:         1,
+	}, report.Counts())
+	assert.Equal(t, []string{"openssl"}, report.Failed())
+	assert.Equal(t, 12500*time.Millisecond, report.Packages[3].Archs[0].Duration())
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, report.Encode(buf))
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *report, decoded)
+}
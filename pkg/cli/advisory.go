@@ -16,6 +16,8 @@ import (
 	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
 	"github.com/wolfi-dev/wolfictl/pkg/versions"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/osv"
 	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
@@ -40,6 +42,7 @@ func cmdAdvisory() *cobra.Command {
 	cmd.AddCommand(cmdAdvisoryValidate())
 	cmd.AddCommand(cmdAdvisoryExport())
 	cmd.AddCommand(cmdAdvisoryMigrate())
+	cmd.AddCommand(cmdAdvisoryAliases())
 
 	return cmd
 }
@@ -82,7 +85,7 @@ func resolveTimestamp(ts string) (v2.Timestamp, error) {
 }
 
 type advisoryRequestParams struct {
-	packageName, vuln, eventType, truePositiveNote, falsePositiveNote, falsePositiveType, timestamp, fixedVersion string
+	packageName, vuln, eventType, truePositiveNote, truePositiveSeverity, falsePositiveNote, falsePositiveType, timestamp, fixedVersion string
 }
 
 func (p *advisoryRequestParams) addFlags(cmd *cobra.Command) {
@@ -91,6 +94,7 @@ func (p *advisoryRequestParams) addFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVarP(&p.eventType, "type", "t", "", fmt.Sprintf("type of event [%s]", strings.Join(v2.EventTypes, ", ")))
 	cmd.Flags().StringVar(&p.truePositiveNote, "tp-note", "", "prose explanation of the true positive (used only for true positives)")
+	cmd.Flags().StringVar(&p.truePositiveSeverity, "tp-severity", "", fmt.Sprintf("distro-specific severity rating of the true positive, overriding upstream data [%s] (used only for true positives)", vuln.SeveritiesString()))
 	cmd.Flags().StringVar(&p.falsePositiveNote, "fp-note", "", "prose explanation of the false positive (used only for false positives)")
 	cmd.Flags().StringVar(&p.falsePositiveType, "fp-type", "", fmt.Sprintf("type of false positive [%s]", strings.Join(v2.FPTypes, ", ")))
 	cmd.Flags().StringVar(&p.timestamp, "timestamp", "now", "timestamp of the event (RFC3339 format)")
@@ -127,7 +131,8 @@ func (p *advisoryRequestParams) advisoryRequest() (advisory.Request, error) {
 
 	case v2.EventTypeTruePositiveDetermination:
 		req.Event.Data = v2.TruePositiveDetermination{
-			Note: p.truePositiveNote,
+			Note:     p.truePositiveNote,
+			Severity: vuln.Severity(p.truePositiveSeverity),
 		}
 	}
 
@@ -158,6 +163,25 @@ func addNoDistroDetectionFlag(val *bool, cmd *cobra.Command) {
 	cmd.Flags().BoolVar(val, "no-distro-detection", false, "do not attempt to auto-detect the distro")
 }
 
+func addAliasesDatasetFlag(val *string, cmd *cobra.Command) {
+	cmd.Flags().StringVar(val, "aliases-dataset", "", "directory containing OSV records (e.g. a clone of the GitHub Advisory Database) used to resolve vulnerability aliases")
+}
+
+// newAliasFinder returns an alias finder for the OSV dataset at the given
+// directory. If no directory is given, it returns nil.
+func newAliasFinder(datasetDir string) (vuln.AliasFinder, error) {
+	if datasetDir == "" {
+		return nil, nil
+	}
+
+	idx, err := osv.NewAliasIndex(os.DirFS(datasetDir))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load aliases dataset from %q: %w", datasetDir, err)
+	}
+
+	return idx, nil
+}
+
 func newAllowedFixedVersionsFunc(apkindexes []*repository.ApkIndex, buildCfgs *configs.Index[config.Configuration]) func(packageName string) []string {
 	return func(packageName string) []string {
 		allowedVersionSet := make(map[string]struct{})
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func cmdAdvisoryAliases() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "aliases",
+		SilenceErrors: true,
+		Short:         "Utilities for managing the aliases of advisories",
+	}
+
+	cmd.AddCommand(cmdAdvisoryAliasesSync())
+
+	return cmd
+}
+
+func cmdAdvisoryAliasesSync() *cobra.Command {
+	p := &aliasesSyncParams{}
+	cmd := &cobra.Command{
+		Use:           "sync",
+		Short:         "add known aliases from an OSV dataset to existing advisories",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.aliasesDataset == "" {
+				return fmt.Errorf("an aliases dataset must be specified")
+			}
+
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("advisories repo dir was left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryFsys := rwos.DirFS(advisoriesRepoDir)
+			advisoryCfgs, err := v2.NewIndex(advisoryFsys)
+			if err != nil {
+				return err
+			}
+
+			aliasFinder, err := newAliasFinder(p.aliasesDataset)
+			if err != nil {
+				return err
+			}
+
+			var selectedPackages []string
+			if p.packageName != "" {
+				selectedPackages = []string{p.packageName}
+			}
+
+			return advisory.SyncAliases(advisory.SyncAliasesOptions{
+				AdvisoryDocs:     advisoryCfgs,
+				AliasFinder:      aliasFinder,
+				SelectedPackages: selectedPackages,
+			})
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type aliasesSyncParams struct {
+	doNotDetectDistro bool
+
+	packageName       string
+	advisoriesRepoDir string
+	aliasesDataset    string
+}
+
+func (p *aliasesSyncParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addPackageFlag(&p.packageName, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	addAliasesDatasetFlag(&p.aliasesDataset, cmd)
+}
@@ -122,8 +122,14 @@ func cmdAdvisoryCreate() *cobra.Command {
 				}
 			}
 
+			aliasFinder, err := newAliasFinder(p.aliasesDataset)
+			if err != nil {
+				return err
+			}
+
 			opts := advisory.CreateOptions{
 				AdvisoryDocs: advisoryCfgs,
+				AliasFinder:  aliasFinder,
 			}
 
 			err = advisory.Create(req, opts)
@@ -147,6 +153,7 @@ type createParams struct {
 	distroRepoDir, advisoriesRepoDir string
 	archs                            []string
 	packageRepositoryURL             string
+	aliasesDataset                   string
 }
 
 func (p *createParams) addFlagsTo(cmd *cobra.Command) {
@@ -158,4 +165,5 @@ func (p *createParams) addFlagsTo(cmd *cobra.Command) {
 	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
 	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64", "aarch64"}, "package architectures to find published versions for")
 	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository")
+	addAliasesDatasetFlag(&p.aliasesDataset, cmd)
 }
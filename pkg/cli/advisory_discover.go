@@ -69,6 +69,11 @@ func cmdAdvisoryDiscover() *cobra.Command {
 				return fmt.Errorf("unable to select packages: %w", err)
 			}
 
+			aliasFinder, err := newAliasFinder(p.aliasesDataset)
+			if err != nil {
+				return err
+			}
+
 			selectedPackages := getSelectedOrDistroPackages(p.packageName, buildCfgs)
 			apiKey := p.resolveNVDAPIKey()
 
@@ -102,6 +107,7 @@ func cmdAdvisoryDiscover() *cobra.Command {
 					Arches:                []string{"x86_64", "aarch64"},
 					VulnerabilityDetector: nvdapi.NewDetector(http.DefaultClient, nvdapi.DefaultHost, apiKey),
 					VulnEvents:            events,
+					AliasFinder:           aliasFinder,
 				})
 				return err
 			})
@@ -134,6 +140,8 @@ type discoverParams struct {
 	packageRepositoryURL string
 
 	nvdAPIKey string
+
+	aliasesDataset string
 }
 
 func (p *discoverParams) addFlagsTo(cmd *cobra.Command) {
@@ -147,6 +155,8 @@ func (p *discoverParams) addFlagsTo(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository")
 
 	cmd.Flags().StringVar(&p.nvdAPIKey, "nvd-api-key", "", fmt.Sprintf("NVD API key (Can also be set via the environment variable '%s'. Using an API key significantly increases the rate limit for API requests. If you need an NVD API key, go to https://nvd.nist.gov/developers/request-an-api-key .)", envVarNameForNVDAPIKey))
+
+	addAliasesDatasetFlag(&p.aliasesDataset, cmd)
 }
 
 func (p *discoverParams) resolveNVDAPIKey() string {
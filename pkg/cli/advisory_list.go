@@ -3,11 +3,15 @@ package cli
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
 )
 
 func cmdAdvisoryList() *cobra.Command {
@@ -39,6 +43,18 @@ func cmdAdvisoryList() *cobra.Command {
 				return err
 			}
 
+			if !slices.Contains(validSortOrders, p.sortBy) {
+				return fmt.Errorf("invalid sort order %q, must be one of [%s]", p.sortBy, strings.Join(validSortOrders, ", "))
+			}
+
+			exploitData, err := p.exploitability.load()
+			if err != nil {
+				return fmt.Errorf("unable to load exploitability data: %w", err)
+			}
+			if p.knownExploited && exploitData == nil {
+				return fmt.Errorf("--known-exploited requires exploitability data, see --exploitability")
+			}
+
 			var cfgs []v2.Document
 			if pkg := p.packageName; pkg != "" {
 				cfgs = advisoryCfgs.Select().WhereName(pkg).Configurations()
@@ -46,7 +62,7 @@ func cmdAdvisoryList() *cobra.Command {
 				cfgs = advisoryCfgs.Select().Configurations()
 			}
 
-			var output string
+			var items []listItem
 
 			for _, cfg := range cfgs {
 				for _, adv := range cfg.Advisories {
@@ -65,21 +81,46 @@ func cmdAdvisoryList() *cobra.Command {
 						continue
 					}
 
-					if p.history {
-						// user wants to see the full history
-						sorted := adv.SortedEvents()
-						for _, event := range sorted {
-							timestamp := event.Timestamp
-							statusDescription := renderListItem(event)
-							output += fmt.Sprintf("%s: %s: %s @ %s\n", cfg.Package.Name, adv.ID, statusDescription, timestamp)
-						}
-
+					exploitability := exploitData.Lookup(append([]string{adv.ID}, adv.Aliases...)...)
+					if p.knownExploited && !exploitability.KnownExploited() {
+						// user only wants to see known exploited vulnerabilities
 						continue
 					}
 
-					statusDescription := renderListItem(adv.Latest())
-					output += fmt.Sprintf("%s: %s: %s\n", cfg.Package.Name, adv.ID, statusDescription)
+					items = append(items, listItem{
+						packageName:    cfg.Package.Name,
+						advisory:       adv,
+						exploitability: exploitability,
+					})
+				}
+			}
+
+			if p.sortBy == sortByExploitability {
+				sort.SliceStable(items, func(i, j int) bool {
+					return items[j].exploitability.Less(items[i].exploitability)
+				})
+			}
+
+			var output string
+
+			for _, item := range items {
+				adv := item.advisory
+				exploitability := renderExploitability(item.exploitability)
+
+				if p.history {
+					// user wants to see the full history
+					sorted := adv.SortedEvents()
+					for _, event := range sorted {
+						timestamp := event.Timestamp
+						statusDescription := renderListItem(event)
+						output += fmt.Sprintf("%s: %s: %s @ %s%s\n", item.packageName, adv.ID, statusDescription, timestamp, exploitability)
+					}
+
+					continue
 				}
+
+				statusDescription := renderListItem(adv.Latest())
+				output += fmt.Sprintf("%s: %s: %s%s\n", item.packageName, adv.ID, statusDescription, exploitability)
 			}
 
 			fmt.Print(output)
@@ -100,6 +141,16 @@ type listParams struct {
 	vuln        string
 	history     bool
 	unresolved  bool
+
+	exploitability exploitabilityParams
+	knownExploited bool
+	sortBy         string
+}
+
+type listItem struct {
+	packageName    string
+	advisory       v2.Advisory
+	exploitability *exploit.Exploitability
 }
 
 func (p *listParams) addFlagsTo(cmd *cobra.Command) {
@@ -112,6 +163,10 @@ func (p *listParams) addFlagsTo(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&p.history, "history", false, "show full history for advisories")
 	cmd.Flags().BoolVar(&p.unresolved, "unresolved", false, "only show advisories considered to be unresolved")
+
+	p.exploitability.addFlagsTo(cmd)
+	cmd.Flags().BoolVar(&p.knownExploited, "known-exploited", false, "only show advisories for vulnerabilities in the KEV catalog")
+	addSortFlag(&p.sortBy, cmd)
 }
 
 func renderListItem(event v2.Event) string {
@@ -130,16 +185,26 @@ func renderListItem(event v2.Event) string {
 				if data, ok := data.Data.(v2.DetectionNVDAPI); ok {
 					expanded = fmt.Sprintf("nvdapi: %s", data.CPEFound)
 				}
+
+			case v2.DetectionTypeScanV1:
+				if data, ok := data.Data.(v2.DetectionScanV1); ok {
+					expanded = fmt.Sprintf("scan/v1: %s@%s (%s)", data.ComponentName, data.ComponentVersion, data.ComponentLocation)
+				}
 			}
 		}
 		return fmt.Sprintf("%s (%s)", t, expanded)
 
 	case v2.EventTypeTruePositiveDetermination:
-		expanded := ""
-		if data, ok := event.Data.(v2.TruePositiveDetermination); ok && data.Note != "" {
-			expanded = data.Note
+		var expanded []string
+		if data, ok := event.Data.(v2.TruePositiveDetermination); ok {
+			if data.Severity != "" {
+				expanded = append(expanded, fmt.Sprintf("severity: %s", data.Severity))
+			}
+			if data.Note != "" {
+				expanded = append(expanded, data.Note)
+			}
 		}
-		return fmt.Sprintf("%s (%s)", t, expanded)
+		return fmt.Sprintf("%s (%s)", t, strings.Join(expanded, "; "))
 
 	case v2.EventTypeFixed:
 		expanded := ""
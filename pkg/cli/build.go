@@ -2,61 +2,160 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"chainguard.dev/apko/pkg/build/types"
 	"chainguard.dev/melange/pkg/build"
 	"chainguard.dev/melange/pkg/config"
+	"github.com/adrg/xdg"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 
+	"github.com/wolfi-dev/wolfictl/pkg/buildenv"
+	"github.com/wolfi-dev/wolfictl/pkg/buildreport"
 	"github.com/wolfi-dev/wolfictl/pkg/dag"
-	"golang.org/x/sync/errgroup"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"golang.org/x/exp/slices"
 )
 
+// defaultDurationsPath is where the durations of builds are kept by default. It's
+// outside of the package directory, so that it isn't published or committed
+// along with the packages.
+var defaultDurationsPath = filepath.Join(xdg.CacheHome, "wolfictl", "build-durations.json")
+
 func cmdBuild() *cobra.Command {
 	var archs []string
 	var dir, pipelineDir, runner, logDir string
 	var jobs int
 	var dryrun bool
 	var extraKeys, extraRepos []string
+	var withDeps, withDependents bool
+	var keepGoing bool
+	var reportPath string
+	var publishedRepos []string
+	var configPath string
+	var envFlags buildenv.Env
+	var durationsPath string
 
-	// TODO: buildworld bool (build deps vs get them from package repo)
-	// TODO: builddownstream bool (build things that depend on listed packages)
 	cmd := &cobra.Command{
-		Use:           "build",
+		Use:   "build [package...]",
+		Short: "Build packages in dependency order",
+		Long: `Build packages in dependency order.
+
+With no arguments, every package in the directory is built. Otherwise, only the
+named packages are built, along with their local build dependencies if
+--with-deps is set, and every local package that depends on them if
+--with-dependents is set.
+
+Each package is built separately for each arch, once its dependencies have been
+built for that arch, so a slow build for one arch doesn't hold up the others.
+Packages aren't built for the archs that they don't list in
+package.target-architecture.
+
+By default, the build stops at the first package that fails. With --keep-going,
+the packages that depend on a failed package are skipped, and every other
+package is still built.
+
+When there are more packages ready to build than --jobs, the ones at the start of
+the longest chains of dependent builds go first, so that long chains like
+gcc -> glibc -> ... don't hold up the end of the build. How long each build takes
+is recorded in the --durations file (in the user's cache directory, by
+default), and used to prioritize and estimate the time left in later builds.
+
+Packages whose APK is already in packages/<arch> are skipped. With
+--published-repo, packages whose exact version (name-version-rEPOCH) is already
+published in that repository for the arch are skipped too, so that only new
+versions are built. Skipped packages don't block the packages that depend on
+them.
+
+The build environment (the keyring and repositories that build dependencies are
+installed from, the signing key, the package namespace, and the source cache)
+defaults to the settings of the distro detected from the package directory, or
+Wolfi's if none is detected. The defaults are overridden by the "build" section
+of the .wolfictl.yaml file in the package directory, and then by flags:
+
+  build:
+    keyring:
+      - https://packages.example.com/os/example.rsa.pub
+    repositories:
+      - https://packages.example.com/os
+    signing-key: example.rsa
+    namespace: example
+    cache-source: gs://example-sources/
+    cache-dir: ./melange-cache/
+
+Relative signing-key and cache-dir paths are relative to the package directory.
+The --keyring-append and --repository-append flags add to the configured
+keyring and repositories.`,
+		Example: `
+# Rebuild openssl and everything that depends on it
+wolfictl build openssl --with-dependents
+
+# Build everything, and report every failure at the end
+wolfictl build --keep-going --report build-report.json
+
+# Only build the package versions that aren't published yet
+wolfictl build --published-repo wolfi
+`,
 		SilenceErrors: true,
-		Args:          cobra.NoArgs,
+		Args:          cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			started := time.Now()
 
 			if jobs == 0 {
 				jobs = runtime.GOMAXPROCS(0)
 			}
-			jobch := make(chan struct{}, jobs)
+			sched := newScheduler()
 
 			if pipelineDir == "" {
 				pipelineDir = filepath.Join(dir, "pipelines")
 			}
 
-			newTask := func(pkg string) *task {
+			if configPath == "" {
+				configPath = filepath.Join(dir, buildenv.ConfigFileName)
+			}
+			env, err := resolveBuildEnv(dir, configPath, envFlags)
+			if err != nil {
+				return err
+			}
+			env.Keyring = append(env.Keyring, extraKeys...)
+			env.Repositories = append(env.Repositories, extraRepos...)
+
+			published, err := publishedAPKs(archs, publishedRepos)
+			if err != nil {
+				return err
+			}
+
+			durations, err := buildreport.LoadDurations(durationsPath)
+			if err != nil {
+				return err
+			}
+
+			newTask := func(pkg, arch string) *task {
 				return &task{
 					pkg:         pkg,
+					arch:        arch,
 					dir:         dir,
 					pipelineDir: pipelineDir,
 					runner:      runner,
-					archs:       archs,
 					dryrun:      dryrun,
 					done:        make(chan struct{}),
-					deps:        map[string]chan struct{}{},
-					jobch:       jobch,
+					deps:        map[string]*task{},
+					sched:       sched,
 					logDir:      logDir,
+					published:   published[arch],
+					env:         env,
 				}
 			}
 
@@ -71,6 +170,11 @@ func cmdBuild() *cobra.Command {
 				return err
 			}
 
+			targets, err := buildTargets(g, pkgs, args, withDeps, withDependents)
+			if err != nil {
+				return err
+			}
+
 			// Only return local packages
 			g, err = g.Filter(dag.FilterLocal())
 			if err != nil {
@@ -88,42 +192,166 @@ func cmdBuild() *cobra.Command {
 				return err
 			}
 
-			tasks := map[string]*task{}
+			// Each package is built by a separate task for each arch.
+			tasks := map[string]map[string]*task{}
+			for _, arch := range archs {
+				tasks[arch] = map[string]*task{}
+			}
+
+			// The outcome of building each package, for each arch.
+			versions := map[string]string{}
+			results := map[string][]buildreport.Arch{}
+
 			for _, pkg := range g.Packages() {
-				if tasks[pkg] == nil {
-					tasks[pkg] = newTask(pkg)
+				if !targets[pkg] {
+					continue
+				}
+				configs := pkgs.Config(pkg, true)
+				if len(configs) == 0 {
+					continue
 				}
+				// The highest version is the one that's built.
+				c := configs[len(configs)-1]
+				versions[pkg] = c.Version()
+
+				for _, arch := range archs {
+					if !supportsArch(c.Package.TargetArchitecture, arch) {
+						log.Printf("skipping %s for %s, which isn't one of its target architectures", pkg, arch)
+						results[pkg] = append(results[pkg], buildreport.Arch{Arch: arch, Status: buildreport.StatusUnsupported})
+						continue
+					}
+					tasks[arch][pkg] = newTask(pkg, arch)
+				}
+			}
+
+			for _, pkg := range g.Packages() {
 				for k, v := range m {
 					// The package list is in the form of "pkg:version",
 					// but we only care about the package name.
-					if strings.HasPrefix(k, pkg+":") {
-						for _, dep := range v {
-							d, _, _ := strings.Cut(dep.Target, ":")
+					if !strings.HasPrefix(k, pkg+":") {
+						continue
+					}
+					for _, dep := range v {
+						d, _, _ := strings.Cut(dep.Target, ":")
+						if d == pkg {
+							continue
+						}
 
-							if tasks[d] == nil {
-								tasks[d] = newTask(d)
+						// A package only waits on its dependencies for the
+						// same arch.
+						for _, arch := range archs {
+							t, dt := tasks[arch][pkg], tasks[arch][d]
+							if t != nil && dt != nil {
+								t.deps[d] = dt
 							}
-							tasks[pkg].deps[d] = tasks[d].done
 						}
 					}
 				}
 			}
+			count := 0
+			for _, arch := range archs {
+				count += len(tasks[arch])
+			}
 
-			if len(tasks) == 0 {
+			if count == 0 {
 				return fmt.Errorf("no packages to build")
 			}
 
-			for _, t := range tasks {
-				go t.start(ctx)
+			prioritize(tasks, durations)
+
+			// The tasks that haven't finished, for estimating the time left. The
+			// estimate is only worth showing if there's a history of builds.
+			remaining := map[*task]bool{}
+			for _, byPkg := range tasks {
+				for _, t := range byPkg {
+					remaining[t] = true
+				}
+			}
+			showETA := len(durations) > 0
+			if showETA {
+				log.Printf("Starting %d builds, %s", count, formatETA(timeLeft(remaining, jobs)))
+			}
+
+			finished := make(chan *task, count)
+			for _, byPkg := range tasks {
+				for _, t := range byPkg {
+					t := t
+					// Queue the tasks that don't wait on anything up front, so
+					// that the first slots go to the highest priority ones too.
+					if len(t.deps) == 0 {
+						sched.queue(t)
+					}
+					go func() {
+						t.start(ctx)
+						finished <- t
+					}()
+				}
 			}
-			count := len(tasks)
+			sched.start(jobs)
 
-			for _, t := range tasks {
-				if err := t.wait(ctx); err != nil {
-					return fmt.Errorf("failed to build %s: %w", t.pkg, err)
+			report := func() *buildreport.Report {
+				var packages []buildreport.Package
+				for pkg, archResults := range results {
+					// List the archs in the order they were given.
+					sort.Slice(archResults, func(i, j int) bool {
+						return slices.Index(archs, archResults[i].Arch) < slices.Index(archs, archResults[j].Arch)
+					})
+					packages = append(packages, buildreport.NewPackage(pkg, versions[pkg], archResults))
 				}
-				delete(tasks, t.pkg)
-				log.Printf("Finished building %s (%d/%d)", t.pkg, count-len(tasks), count)
+				return buildreport.New(started, time.Now(), packages)
+			}
+
+			// saveDurations records how long the packages took to build, for
+			// future builds.
+			saveDurations := func(r *buildreport.Report) {
+				if dryrun {
+					return
+				}
+				durations.Record(r)
+				if err := durations.Save(durationsPath); err != nil {
+					log.Warnf("saving build durations: %v", err)
+				}
+			}
+
+			for i := 0; i < count; i++ {
+				t := <-finished
+				result := t.result()
+				results[t.pkg] = append(results[t.pkg], result)
+				delete(remaining, t)
+
+				progress := fmt.Sprintf("%d/%d", i+1, count)
+				if showETA && len(remaining) > 0 {
+					progress += ", " + formatETA(timeLeft(remaining, jobs))
+				}
+
+				switch result.Status {
+				case buildreport.StatusFailed:
+					log.Errorf("Failed to build %s for %s (%s): %v", t.pkg, t.arch, progress, t.err)
+				case buildreport.StatusBlocked:
+					log.Warnf("Skipped %s for %s, blocked by %s (%s)", t.pkg, t.arch, strings.Join(result.BlockedBy, ", "), progress)
+				default:
+					log.Printf("Finished building %s for %s (%s)", t.pkg, t.arch, progress)
+				}
+
+				if t.err != nil && !keepGoing {
+					cancel()
+					r := report()
+					saveDurations(r)
+					if err := writeBuildReport(reportPath, r); err != nil {
+						log.Errorf("writing build report: %v", err)
+					}
+					return fmt.Errorf("failed to build %s for %s: %w", t.pkg, t.arch, t.err)
+				}
+			}
+
+			r := report()
+			saveDurations(r)
+			if err := writeBuildReport(reportPath, r); err != nil {
+				return err
+			}
+
+			if failed := r.Failed(); len(failed) > 0 {
+				return fmt.Errorf("%d packages failed to build: %s", len(failed), strings.Join(failed, ", "))
 			}
 			return nil
 		},
@@ -137,41 +365,146 @@ func cmdBuild() *cobra.Command {
 	cmd.Flags().BoolVar(&dryrun, "dry-run", false, "print commands instead of executing them")
 	cmd.Flags().StringSliceVarP(&extraKeys, "keyring-append", "k", []string{}, "path to extra keys to include in the build environment keyring")
 	cmd.Flags().StringSliceVarP(&extraRepos, "repository-append", "r", []string{}, "path to extra repositories to include in the build environment")
+	cmd.Flags().BoolVar(&withDeps, "with-deps", false, "also build the local build dependencies (transitively) of the named packages")
+	cmd.Flags().BoolVar(&withDependents, "with-dependents", false, "also build the local packages that depend (transitively) on the named packages")
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, "keep building packages that don't depend on a failed package, instead of stopping at the first failure")
+	cmd.Flags().StringSliceVar(&publishedRepos, "published-repo", []string{}, "skip packages whose exact version is already in this repository's APKINDEX for the arch: a URL, a local repository directory, or a name like \"wolfi\" (can be repeated)")
+	cmd.Flags().StringVar(&reportPath, "report", "", "write a report of each package's build status to this path, as markdown if it ends in .md, or as JSON otherwise")
+	cmd.Flags().StringVar(&durationsPath, "durations", defaultDurationsPath, "file of how long previous builds took, used to prioritize builds and estimate the time left, and updated after each build")
+	cmd.Flags().StringVar(&configPath, "config", "", fmt.Sprintf("config file with the build environment (default is %s in --dir)", buildenv.ConfigFileName))
+	cmd.Flags().StringVar(&envFlags.SigningKey, "signing-key", "", "path to the key to sign packages with, relative to --dir (default is local-melange.rsa)")
+	cmd.Flags().StringVar(&envFlags.Namespace, "namespace", "", "namespace of the built packages (default is the distro's name)")
+	cmd.Flags().StringVar(&envFlags.CacheSource, "cache-source", "", "location of a cache of package sources to populate --cache-dir from (default is the distro's)")
+	cmd.Flags().StringVar(&envFlags.CacheDir, "cache-dir", "", "directory to cache package sources in, relative to --dir (default is ./melange-cache/)")
 	cmd.Flags().StringVar(&logDir, "log-dir", "buildlogs", "subdirectory where buildlogs will be written when specified (packages/$arch/buildlogs/$apk.log)")
 	return cmd
 }
 
+// buildTargets returns the set of origin packages to build. With no names, that's
+// every local package. Otherwise, it's the named packages, plus their
+// dependencies and/or dependents in g, if requested.
+func buildTargets(g *dag.Graph, pkgs *dag.Packages, names []string, withDeps, withDependents bool) (map[string]bool, error) {
+	targets := map[string]bool{}
+
+	if len(names) == 0 {
+		if withDeps || withDependents {
+			return nil, fmt.Errorf("--with-deps and --with-dependents require package names")
+		}
+		for _, pkg := range pkgs.PackageNames() {
+			targets[pkg] = true
+		}
+		return targets, nil
+	}
+
+	// Sub resolves subpackages and provides to their origin packages.
+	named, err := pkgs.Sub(names...)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range named.PackageNames() {
+		targets[pkg] = true
+	}
+
+	if withDeps {
+		sub, err := g.SubgraphWithRoots(names)
+		if err != nil {
+			return nil, fmt.Errorf("finding dependencies of %s: %w", strings.Join(names, ", "), err)
+		}
+		for _, pkg := range sub.Packages() {
+			targets[pkg] = true
+		}
+	}
+
+	if withDependents {
+		sub, err := g.SubgraphWithLeaves(names)
+		if err != nil {
+			return nil, fmt.Errorf("finding dependents of %s: %w", strings.Join(names, ", "), err)
+		}
+		for _, pkg := range sub.Packages() {
+			targets[pkg] = true
+		}
+	}
+
+	return targets, nil
+}
+
+// supportsArch reports whether a package with the given target architectures
+// can be built for the given arch. Like melange, it treats no target
+// architectures, or just "all", as every arch.
+func supportsArch(targetArchs []string, arch string) bool {
+	if len(targetArchs) == 0 || (len(targetArchs) == 1 && targetArchs[0] == "all") {
+		return true
+	}
+
+	return slices.Contains(targetArchs, types.ParseArchitecture(arch).ToAPK())
+}
+
 type task struct {
-	pkg, dir, pipelineDir, runner, logDir string
-	archs                                 []string
-	dryrun                                bool
+	pkg, arch, dir, pipelineDir, runner, logDir string
+	dryrun                                      bool
+
+	env buildenv.Env
+
+	// published holds the APKs already published for the arch, which don't need
+	// to be built.
+	published map[string]bool
+
+	// estimate is how long the task is expected to take, and priority is how
+	// long the longest chain of tasks that starts with it is expected to take.
+	// See prioritize.
+	estimate, priority time.Duration
 
-	err         error
-	deps        map[string]chan struct{}
-	done, jobch chan struct{}
+	err       error
+	status    buildreport.Status
+	duration  time.Duration
+	logPath   string
+	blockedBy []string
+	deps      map[string]*task
+	done      chan struct{}
+	sched     *scheduler
 }
 
+// errBlocked is the error of a task that wasn't run because one of its
+// dependencies failed.
+var errBlocked = errors.New("blocked by a failed dependency")
+
 func (t *task) start(ctx context.Context) {
-	log.Printf("task %q waiting on %q", t.pkg, maps.Keys(t.deps))
+	log.Printf("task %q (%s) waiting on %q", t.pkg, t.arch, maps.Keys(t.deps))
 
 	defer close(t.done) // signal that we're done, one way or another.
 	tick := time.NewTicker(30 * time.Second)
 	defer tick.Stop()
 	for depname, dep := range t.deps {
-		select {
-		case <-tick.C:
-			log.Printf("task %q waiting on %q", t.pkg, maps.Keys(t.deps))
-		case <-dep:
-			delete(t.deps, depname)
-			// this dep is done.
-		case <-ctx.Done():
-			return // cancelled or failed
+		for waiting := true; waiting; {
+			select {
+			case <-tick.C:
+				log.Printf("task %q (%s) waiting on %q", t.pkg, t.arch, maps.Keys(t.deps))
+			case <-dep.done:
+				// this dep is done.
+				waiting = false
+			case <-ctx.Done():
+				t.err = ctx.Err()
+				return // cancelled or failed
+			}
 		}
+		if dep.err != nil {
+			t.blockedBy = append(t.blockedBy, depname)
+		}
+		delete(t.deps, depname)
+	}
+
+	if len(t.blockedBy) > 0 {
+		sort.Strings(t.blockedBy)
+		t.err = errBlocked
+		return
 	}
 
-	// Block on jobch, to limit concurrency. Remove from jobch when done.
-	t.jobch <- struct{}{}
-	defer func() { <-t.jobch }()
+	// Wait for a slot to run in, to limit concurrency. Release it when done.
+	if err := t.sched.acquire(ctx, t); err != nil {
+		t.err = err
+		return
+	}
+	defer t.sched.release()
 
 	// all deps are done and we're clear to launch.
 	t.err = t.do(ctx)
@@ -183,76 +516,172 @@ func (t *task) do(ctx context.Context) error {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	var bcs []*build.Build
-	for _, arch := range t.archs {
-		// See if we already have the package built.
-		apk := fmt.Sprintf("%s-%s-r%d.apk", cfg.Package.Name, cfg.Package.Version, cfg.Package.Epoch)
-		apkPath := filepath.Join(t.dir, "packages", arch, apk)
-		if _, err := os.Stat(apkPath); err == nil {
-			log.Printf("skipping %s, already built", apkPath)
-			continue
-		}
-
-		sdir := filepath.Join(t.dir, t.pkg)
-		if _, err := os.Stat(sdir); os.IsNotExist(err) {
-			if err := os.MkdirAll(sdir, os.ModePerm); err != nil {
-				return fmt.Errorf("creating source directory %s: %v", sdir, err)
-			}
-		} else if err != nil {
-			return fmt.Errorf("creating source directory: %v", err)
-		}
+	// See if we already have the package built.
+	apk := fmt.Sprintf("%s-%s-r%d.apk", cfg.Package.Name, cfg.Package.Version, cfg.Package.Epoch)
+	apkPath := filepath.Join(t.dir, "packages", t.arch, apk)
+	if _, err := os.Stat(apkPath); err == nil {
+		log.Printf("skipping %s, already built", apkPath)
+		t.status = buildreport.StatusSkippedExisting
+		return nil
+	}
+	if t.published[apk] {
+		log.Printf("skipping %s, already published", apkPath)
+		t.status = buildreport.StatusSkippedExisting
+		return nil
+	}
 
-		logPolicy := []string{"builtin:stderr"}
-		if t.logDir != "" {
-			// mirror wolfi/os Makefile semantics of: ./packages/$arch/buildlogs/$apk.log
-			logPolicy = append(logPolicy, fmt.Sprintf("%s/%s.log",
-				filepath.Join(t.dir, "packages", string(types.ParseArchitecture(arch)), t.logDir),
-				apk,
-			))
+	sdir := filepath.Join(t.dir, t.pkg)
+	if _, err := os.Stat(sdir); os.IsNotExist(err) {
+		if err := os.MkdirAll(sdir, os.ModePerm); err != nil {
+			return fmt.Errorf("creating source directory %s: %v", sdir, err)
 		}
+	} else if err != nil {
+		return fmt.Errorf("creating source directory: %v", err)
+	}
 
-		fn := fmt.Sprintf("%s.yaml", t.pkg)
-		if t.dryrun {
-			log.Printf("DRYRUN: would have built %s", apkPath)
-			continue
-		}
-		log.Println("will build:", apkPath)
-		bc, err := build.New(ctx,
-			build.WithArch(types.ParseArchitecture(arch)),
-			build.WithConfig(filepath.Join(t.dir, fn)),
-			build.WithPipelineDir(t.pipelineDir),
-			build.WithExtraKeys([]string{"https://packages.wolfi.dev/os/wolfi-signing.rsa.pub"}),
-			build.WithExtraRepos([]string{"https://packages.wolfi.dev/os"}),
-			build.WithSigningKey(filepath.Join(t.dir, "local-melange.rsa")),
-			build.WithRunner(t.runner),
-			build.WithEnvFile(filepath.Join(t.dir, fmt.Sprintf("build-%s.env", arch))),
-			build.WithNamespace("wolfi"),
-			build.WithLogPolicy(logPolicy),
-			build.WithSourceDir(sdir),
-			build.WithCacheSource("gs://wolfi-sources/"),
-			build.WithCacheDir("./melange-cache/"), // TODO: flag
-			build.WithOutDir(filepath.Join(t.dir, "packages")),
+	logPolicy := []string{"builtin:stderr"}
+	var logPath string
+	if t.logDir != "" {
+		// mirror wolfi/os Makefile semantics of: ./packages/$arch/buildlogs/$apk.log
+		logPath = fmt.Sprintf("%s/%s.log",
+			filepath.Join(t.dir, "packages", string(types.ParseArchitecture(t.arch)), t.logDir),
+			apk,
 		)
+		logPolicy = append(logPolicy, logPath)
+	}
+
+	fn := fmt.Sprintf("%s.yaml", t.pkg)
+	if t.dryrun {
+		log.Printf("DRYRUN: would have built %s", apkPath)
+		t.status = buildreport.StatusDryRun
+		return nil
+	}
+	t.logPath = logPath
+	log.Println("will build:", apkPath)
+	bc, err := build.New(ctx,
+		build.WithArch(types.ParseArchitecture(t.arch)),
+		build.WithConfig(filepath.Join(t.dir, fn)),
+		build.WithPipelineDir(t.pipelineDir),
+		build.WithExtraKeys(t.env.Keyring),
+		build.WithExtraRepos(t.env.Repositories),
+		build.WithSigningKey(t.env.SigningKey),
+		build.WithRunner(t.runner),
+		build.WithEnvFile(filepath.Join(t.dir, fmt.Sprintf("build-%s.env", t.arch))),
+		build.WithNamespace(t.env.Namespace),
+		build.WithLogPolicy(logPolicy),
+		build.WithSourceDir(sdir),
+		build.WithCacheSource(t.env.CacheSource),
+		build.WithCacheDir(t.env.CacheDir),
+		build.WithOutDir(filepath.Join(t.dir, "packages")),
+	)
+	if errors.Is(err, build.ErrSkipThisArch) {
+		log.Printf("skipping %s, %s isn't one of its target architectures", apkPath, t.arch)
+		t.status = buildreport.StatusUnsupported
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = bc.BuildPackage(ctx)
+	t.duration = time.Since(start)
+	if err != nil {
+		return err
+	}
+	t.status = buildreport.StatusBuilt
+	return nil
+}
+
+// result returns the outcome of the task, for the build report. It must only be
+// called once the task is done.
+func (t *task) result() buildreport.Arch {
+	a := buildreport.Arch{
+		Arch:            t.arch,
+		Status:          t.status,
+		DurationSeconds: t.duration.Seconds(),
+		LogPath:         t.logPath,
+	}
+
+	switch {
+	case errors.Is(t.err, errBlocked):
+		a.Status = buildreport.StatusBlocked
+		a.BlockedBy = t.blockedBy
+	case t.err != nil:
+		a.Status = buildreport.StatusFailed
+		a.Error = t.err.Error()
+	}
+
+	return a
+}
+
+// resolveBuildEnv returns the build environment: the defaults for the distro
+// whose repo is cloned in dir (or Wolfi, if dir isn't a known distro's repo),
+// overridden by the config file at the given path, if it exists, and then by the
+// settings given as flags. Relative paths in it are resolved against dir.
+func resolveBuildEnv(dir, configPath string, flags buildenv.Env) (buildenv.Env, error) {
+	d, err := distro.DetectRepo(dir)
+	switch {
+	case errors.Is(err, distro.ErrNotDistroRepo):
+		log.Warnf("unable to detect distro, so falling back to Wolfi's build environment defaults: %v", err)
+		d, err = distro.ByName("Wolfi")
 		if err != nil {
-			return err
+			return buildenv.Env{}, err
 		}
-		bcs = append(bcs, bc)
+	case err != nil:
+		return buildenv.Env{}, fmt.Errorf("failed to detect distro: %w", err)
 	}
-	var errg errgroup.Group
-	for _, bc := range bcs {
-		bc := bc
-		errg.Go(func() error {
-			return bc.BuildPackage(ctx)
-		})
+	log.Printf("using %s build environment defaults", d.Name)
+
+	cfg, err := buildenv.Load(configPath)
+	if err != nil {
+		return buildenv.Env{}, err
 	}
-	return errg.Wait()
+
+	return buildenv.Default(d).Merge(cfg).Merge(flags).ResolvePaths(dir), nil
 }
 
-func (t *task) wait(ctx context.Context) error {
-	select {
-	case <-t.done:
-		return t.err
-	case <-ctx.Done():
-		return ctx.Err()
+// publishedAPKs returns the file names of the APKs published in the given
+// repositories, for each of the given archs. Repositories can be given by the
+// names used by the apk command, e.g. "wolfi".
+func publishedAPKs(archs, repositories []string) (map[string]map[string]bool, error) {
+	published := map[string]map[string]bool{}
+	for _, arch := range archs {
+		published[arch] = map[string]bool{}
+		for _, repo := range repositories {
+			if url, ok := repos[repo]; ok {
+				repo = url
+			}
+
+			idx, err := index.Index(string(types.ParseArchitecture(arch).ToAPK()), repo)
+			if err != nil {
+				return nil, fmt.Errorf("getting %s index of published packages from %s: %w", arch, repo, err)
+			}
+			for _, p := range idx.Packages {
+				published[arch][fmt.Sprintf("%s-%s.apk", p.Name, p.Version)] = true
+			}
+			log.Printf("found %d %s packages published in %s", len(idx.Packages), arch, repo)
+		}
+	}
+	return published, nil
+}
+
+// writeBuildReport writes the report to the given path, as markdown if the path
+// ends in ".md", or as JSON otherwise. If the path is empty, it does nothing.
+func writeBuildReport(path string, report *buildreport.Report) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating build report: %w", err)
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		_, err := fmt.Fprint(f, renderBuildReportMarkdown(report))
+		return err
 	}
+	return report.Encode(f)
 }
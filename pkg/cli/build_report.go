@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wolfi-dev/wolfictl/pkg/buildreport"
+)
+
+// renderBuildReportMarkdown renders the build report as a Markdown document:
+// a summary of the package counts by status, the failures and blocked packages,
+// and a table of every package's outcome for each architecture.
+func renderBuildReportMarkdown(report *buildreport.Report) string {
+	sb := new(strings.Builder)
+
+	counts := report.Counts()
+	var summary []string
+	for _, s := range buildreport.Statuses {
+		if counts[s] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+	if len(summary) == 0 {
+		summary = append(summary, "no packages")
+	}
+
+	fmt.Fprintf(sb, "## Build report\n\n")
+	fmt.Fprintf(sb, "%s, in %s.\n\n", strings.Join(summary, ", "), report.Finished.Sub(report.Started).Round(time.Second))
+
+	var failures, blocked []string
+	for _, p := range report.Packages {
+		switch p.Status {
+		case buildreport.StatusFailed:
+			var reasons []string
+			for _, a := range p.Archs {
+				if a.Error != "" {
+					reasons = append(reasons, fmt.Sprintf("%s: %s", a.Arch, a.Error))
+				}
+			}
+			failures = append(failures, fmt.Sprintf("- **%s**: %s", p.Name, strings.Join(reasons, "; ")))
+
+		case buildreport.StatusBlocked:
+			blocked = append(blocked, fmt.Sprintf("- **%s**, blocked by %s", p.Name, strings.Join(p.BlockedBy, ", ")))
+		}
+	}
+
+	if len(failures) > 0 {
+		fmt.Fprintf(sb, "### Failed\n\n%s\n\n", strings.Join(failures, "\n"))
+	}
+	if len(blocked) > 0 {
+		fmt.Fprintf(sb, "### Blocked\n\n%s\n\n", strings.Join(blocked, "\n"))
+	}
+
+	fmt.Fprintf(sb, "### Packages\n\n")
+	fmt.Fprintf(sb, "| Package | Version | Arch | Status | Duration | Log |\n")
+	fmt.Fprintf(sb, "|---------|---------|------|--------|----------|-----|\n")
+	for _, p := range report.Packages {
+		archs := p.Archs
+		if len(archs) == 0 {
+			// The package wasn't attempted for any architecture, e.g. because it
+			// doesn't support any of them.
+			archs = []buildreport.Arch{{Status: p.Status}}
+		}
+
+		for _, a := range archs {
+			var duration string
+			if a.DurationSeconds > 0 {
+				duration = a.Duration().Round(time.Second).String()
+			}
+			fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(p.Name),
+				escapeMarkdownCell(p.Version),
+				a.Arch,
+				a.Status,
+				duration,
+				escapeMarkdownCell(a.LogPath),
+			)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wolfi-dev/wolfictl/pkg/buildreport"
+)
+
+// scheduler limits how many tasks run at once. When a slot frees up, it goes to
+// the waiting task with the highest priority, rather than to whichever task
+// happens to be woken first.
+type scheduler struct {
+	mu      sync.Mutex
+	free    int
+	waiting waitQueue
+	queued  map[*task]*waiter
+}
+
+// newScheduler returns a scheduler with no slots, so that the tasks that can
+// run straight away can be queued before any of them are given a slot. See
+// start.
+func newScheduler() *scheduler {
+	return &scheduler{queued: map[*task]*waiter{}}
+}
+
+// start adds the given number of slots, and gives them to the waiting tasks.
+func (s *scheduler) start(jobs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.free += jobs
+	s.dispatchLocked()
+}
+
+// queue adds t to the tasks waiting for a slot, ahead of its call to acquire.
+func (s *scheduler) queue(t *task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued[t] = s.pushLocked(t)
+}
+
+// acquire blocks until there's a slot for t to run in, or ctx is done. Each
+// successful call must be followed by a call to release.
+func (s *scheduler) acquire(ctx context.Context, t *task) error {
+	s.mu.Lock()
+	w, ok := s.queued[t]
+	if ok {
+		delete(s.queued, t)
+	} else {
+		w = s.pushLocked(t)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if w.index >= 0 {
+			heap.Remove(&s.waiting, w.index)
+		} else {
+			// We were given a slot as ctx was done, so pass it on.
+			s.free++
+			s.dispatchLocked()
+		}
+		return ctx.Err()
+	}
+}
+
+// release gives up a slot, to the highest priority waiting task, if any.
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.free++
+	s.dispatchLocked()
+}
+
+func (s *scheduler) pushLocked(t *task) *waiter {
+	w := &waiter{task: t, ready: make(chan struct{})}
+	heap.Push(&s.waiting, w)
+	s.dispatchLocked()
+	return w
+}
+
+// dispatchLocked gives the free slots to the highest priority waiting tasks.
+func (s *scheduler) dispatchLocked() {
+	for s.free > 0 && s.waiting.Len() > 0 {
+		w := heap.Pop(&s.waiting).(*waiter) //nolint:errcheck
+		close(w.ready)
+		s.free--
+	}
+}
+
+type waiter struct {
+	task  *task
+	ready chan struct{}
+	index int
+}
+
+// waitQueue is a heap of waiters, with the highest priority task first.
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	a, b := q[i].task, q[j].task
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	// Break ties consistently.
+	if a.pkg != b.pkg {
+		return a.pkg < b.pkg
+	}
+	return a.arch < b.arch
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x any) {
+	w := x.(*waiter) //nolint:errcheck
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() any {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
+
+// prioritize sets each task's estimated duration, from the durations of
+// previous builds, and its priority: the estimated duration of the longest
+// chain of tasks that starts with it, i.e. its own estimate plus the highest
+// priority of the tasks that depend on it. Running the tasks at the start of
+// long chains (like gcc → glibc → …) first keeps them from holding up the end of
+// the build.
+func prioritize(tasks map[string]map[string]*task, durations buildreport.Durations) {
+	dependents := map[*task][]*task{}
+	for arch, byPkg := range tasks {
+		for pkg, t := range byPkg {
+			t.estimate = durations.Estimate(arch, pkg)
+			for _, dep := range t.deps {
+				dependents[dep] = append(dependents[dep], t)
+			}
+		}
+	}
+
+	done := map[*task]bool{}
+	var visit func(t *task) time.Duration
+	visit = func(t *task) time.Duration {
+		if done[t] {
+			return t.priority
+		}
+		var longest time.Duration
+		for _, d := range dependents[t] {
+			if p := visit(d); p > longest {
+				longest = p
+			}
+		}
+		t.priority = t.estimate + longest
+		done[t] = true
+		return t.priority
+	}
+
+	for _, byPkg := range tasks {
+		for _, t := range byPkg {
+			visit(t)
+		}
+	}
+}
+
+// timeLeft estimates how long it'll take to run the given tasks with the given
+// number of jobs: the longer of their critical path and their total estimated
+// duration shared between the jobs.
+func timeLeft(remaining map[*task]bool, jobs int) time.Duration {
+	var longest, total time.Duration
+	for t := range remaining {
+		if t.priority > longest {
+			longest = t.priority
+		}
+		total += t.estimate
+	}
+
+	if shared := total / time.Duration(jobs); shared > longest {
+		return shared
+	}
+	return longest
+}
+
+// formatETA describes how much time is left, and when that'll be.
+func formatETA(left time.Duration) string {
+	return fmt.Sprintf("about %s left, ETA %s", left.Round(time.Second), time.Now().Add(left).Format("15:04"))
+}
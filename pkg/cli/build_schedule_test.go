@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wolfi-dev/wolfictl/pkg/buildreport"
+)
+
+func TestScheduler_priority(t *testing.T) {
+	tasks := []*task{
+		{pkg: "low", priority: time.Minute},
+		{pkg: "high", priority: time.Hour},
+		{pkg: "mid", priority: 10 * time.Minute},
+	}
+
+	s := newScheduler()
+	for _, tk := range tasks {
+		s.queue(tk)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for _, tk := range tasks {
+		tk := tk
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.acquire(context.Background(), tk))
+			mu.Lock()
+			order = append(order, tk.pkg)
+			mu.Unlock()
+			s.release()
+		}()
+	}
+
+	// With one slot, the tasks run one at a time, highest priority first.
+	s.start(1)
+	wg.Wait()
+
+	assert.Equal(t, []string{"high", "mid", "low"}, order)
+}
+
+func TestScheduler_cancelWhileWaiting(t *testing.T) {
+	running := &task{pkg: "running", priority: time.Minute}
+	waiting := &task{pkg: "waiting", priority: time.Hour}
+
+	s := newScheduler()
+	s.start(1)
+	require.NoError(t, s.acquire(context.Background(), running))
+
+	s.queue(waiting)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.acquire(ctx, waiting), context.Canceled)
+
+	// The cancelled task is no longer waiting, so the slot stays free once it's
+	// released.
+	s.release()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(t, 0, s.waiting.Len())
+	assert.Equal(t, 1, s.free)
+}
+
+func TestScheduler_cancelAfterGranted(t *testing.T) {
+	// When a task is given a slot just as its context is done, acquire may
+	// return either way. If it returns an error, it must pass the slot on. Try a
+	// number of times, so that both outcomes are checked.
+	for i := 0; i < 50; i++ {
+		granted := &task{pkg: "granted", priority: time.Hour}
+		next := &task{pkg: "next", priority: time.Minute}
+
+		s := newScheduler()
+		s.queue(granted)
+		s.queue(next)
+		s.start(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := s.acquire(ctx, granted)
+
+		s.mu.Lock()
+		assert.Equal(t, 0, s.free, "the slot should be in use")
+		if err != nil {
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, 0, s.waiting.Len(), "the slot should be passed on to the next task")
+		} else {
+			assert.Equal(t, 1, s.waiting.Len(), "the next task should still be waiting")
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			assert.NoError(t, s.acquire(ctx, next))
+			cancel()
+		}
+	}
+}
+
+func TestPrioritize(t *testing.T) {
+	// A diamond: b and c depend on a, and d depends on b and c.
+	newTask := func(pkg string, deps ...*task) *task {
+		tk := &task{pkg: pkg, arch: "x86_64", deps: map[string]*task{}}
+		for _, dep := range deps {
+			tk.deps[dep.pkg] = dep
+		}
+		return tk
+	}
+	a := newTask("a")
+	b := newTask("b", a)
+	c := newTask("c", a)
+	d := newTask("d", b, c)
+
+	tasks := map[string]map[string]*task{
+		"x86_64": {"a": a, "b": b, "c": c, "d": d},
+	}
+	durations := buildreport.Durations{
+		"x86_64": {"a": 60, "b": 600, "c": 60, "d": 120},
+	}
+
+	prioritize(tasks, durations)
+
+	cases := []struct {
+		task               *task
+		estimate, priority time.Duration
+	}{
+		{a, time.Minute, 13 * time.Minute},
+		{b, 10 * time.Minute, 12 * time.Minute},
+		{c, time.Minute, 3 * time.Minute},
+		{d, 2 * time.Minute, 2 * time.Minute},
+	}
+	for _, tt := range cases {
+		assert.Equal(t, tt.estimate, tt.task.estimate, "estimate of %s", tt.task.pkg)
+		assert.Equal(t, tt.priority, tt.task.priority, "priority of %s", tt.task.pkg)
+	}
+
+	remaining := map[*task]bool{a: true, b: true, c: true, d: true}
+
+	// With one job, every task runs one after another.
+	assert.Equal(t, 14*time.Minute, timeLeft(remaining, 1))
+
+	// With enough jobs, the critical path a → b → d is what's left.
+	assert.Equal(t, 13*time.Minute, timeLeft(remaining, 4))
+
+	delete(remaining, a)
+	assert.Equal(t, 12*time.Minute, timeLeft(remaining, 4))
+}
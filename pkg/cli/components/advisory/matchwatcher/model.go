@@ -314,7 +314,12 @@ func hyperlinkCVE(id string) string {
 		return id
 	}
 
-	return termlink.Link(id, fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%s", id))
+	u := vuln.URL(id)
+	if u == "" {
+		return id
+	}
+
+	return termlink.Link(id, u)
 }
 
 func renderSeverity(severity vuln.Severity) string {
@@ -18,6 +18,7 @@ type Model struct {
 	allowedPackagesFunc        func() []string
 	allowedVulnerabilitiesFunc func(packageName string) []string
 	allowedFixedVersionsFunc   func(packageName string) []string
+	detection                  v2.Detection
 
 	// input/output data
 	Request advisory.Request
@@ -235,6 +236,10 @@ type Configuration struct {
 	AllowedPackagesFunc        func() []string
 	AllowedVulnerabilitiesFunc func(packageName string) []string
 	AllowedFixedVersionsFunc   func(packageName string) []string
+
+	// Detection is the data recorded if the user chooses a detection event. If
+	// it's not set, the detection is recorded as manual.
+	Detection *v2.Detection
 }
 
 func New(config Configuration) Model {
@@ -244,6 +249,11 @@ func New(config Configuration) Model {
 		allowedPackagesFunc:        config.AllowedPackagesFunc,
 		allowedVulnerabilitiesFunc: config.AllowedVulnerabilitiesFunc,
 		allowedFixedVersionsFunc:   config.AllowedFixedVersionsFunc,
+		detection:                  v2.Detection{Type: v2.DetectionTypeManual},
+	}
+
+	if config.Detection != nil {
+		m.detection = *config.Detection
 	}
 
 	m, _ = m.addMissingFields()
@@ -356,7 +366,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// We should move this business logic snippet somewhere else eventually.
 			if m.Request.Event.Type == v2.EventTypeDetection {
-				m.Request.Event.Data = v2.Detection{Type: v2.DetectionTypeManual}
+				m.Request.Event.Data = m.detection
 			}
 
 			m.fields[m.focusIndex] = sel
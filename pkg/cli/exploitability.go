@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
+)
+
+// exploitabilityParams are the flags for commands that can enrich
+// vulnerabilities with EPSS and KEV data.
+type exploitabilityParams struct {
+	enabled  bool
+	epssFile string
+	kevFile  string
+}
+
+func (p *exploitabilityParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&p.enabled, "exploitability", false, fmt.Sprintf("enrich vulnerabilities with EPSS scores and KEV catalog membership, using locally downloaded data (default locations: %q, %q)", exploit.DefaultEPSSPath, exploit.DefaultKEVPath))
+	cmd.Flags().StringVar(&p.epssFile, "epss-file", "", "path to a local EPSS scores CSV file (optionally gzipped), implies --exploitability")
+	cmd.Flags().StringVar(&p.kevFile, "kev-file", "", "path to a local CISA KEV catalog JSON file, implies --exploitability")
+}
+
+// load returns the exploitability data requested by the user, or nil if
+// enrichment wasn't requested.
+func (p *exploitabilityParams) load() (*exploit.Data, error) {
+	if !p.enabled && p.epssFile == "" && p.kevFile == "" {
+		return nil, nil
+	}
+
+	return exploit.Load(p.epssFile, p.kevFile)
+}
+
+const (
+	sortByID             = "id"
+	sortByExploitability = "exploitability"
+)
+
+var validSortOrders = []string{sortByID, sortByExploitability}
+
+func addSortFlag(val *string, cmd *cobra.Command) {
+	cmd.Flags().StringVar(val, "sort", sortByID, fmt.Sprintf("sort order for vulnerabilities (%s), sorting by exploitability lists known exploited vulnerabilities first, followed by the highest EPSS probabilities", strings.Join(validSortOrders, "|")))
+}
+
+func renderExploitability(e *exploit.Exploitability) string {
+	if e == nil {
+		return ""
+	}
+
+	var parts []string
+	if e.KnownExploited() {
+		parts = append(parts, styleKEV.Render("KNOWN EXPLOITED"))
+	}
+	if e.EPSS != nil {
+		parts = append(parts, styleSubtle.Render(fmt.Sprintf("EPSS %.2f%%", e.EPSS.Probability*100)))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " " + strings.Join(parts, " ")
+}
+
+var styleKEV = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff")).Background(lipgloss.Color("#cc0000")).Padding(0, 1)
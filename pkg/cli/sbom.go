@@ -18,10 +18,14 @@ import (
 )
 
 const (
-	sbomFormatOutline  = "outline"
-	sbomFormatSyftJSON = "syft-json"
+	sbomFormatOutline       = "outline"
+	sbomFormatSyftJSON      = "syft-json"
+	sbomFormatSPDXJSON      = "spdx-json"
+	sbomFormatCycloneDXJSON = "cyclonedx-json"
 )
 
+var validSBOMFormats = []string{sbomFormatOutline, sbomFormatSyftJSON, sbomFormatSPDXJSON, sbomFormatCycloneDXJSON}
+
 func cmdSBOM() *cobra.Command {
 	p := &sbomParams{}
 	cmd := &cobra.Command{
@@ -31,8 +35,8 @@ func cmdSBOM() *cobra.Command {
 		SilenceErrors: true,
 		Args:          cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if !slices.Contains([]string{sbomFormatOutline, sbomFormatSyftJSON}, p.outputFormat) {
-				return fmt.Errorf("invalid output format %q, must be one of [%s]", p.outputFormat, strings.Join([]string{sbomFormatOutline, sbomFormatSyftJSON}, ", "))
+			if !slices.Contains(validSBOMFormats, p.outputFormat) {
+				return fmt.Errorf("invalid output format %q, must be one of [%s]", p.outputFormat, strings.Join(validSBOMFormats, ", "))
 			}
 
 			apkFilePath := args[0]
@@ -58,8 +62,14 @@ func cmdSBOM() *cobra.Command {
 				tree := newPackageTree(s.Artifacts.Packages.Sorted())
 				fmt.Println(tree.render())
 
-			case sbomFormatSyftJSON:
-				jsonReader, err := sbom.ToSyftJSON(s)
+			case sbomFormatSyftJSON, sbomFormatSPDXJSON, sbomFormatCycloneDXJSON:
+				encode := map[string]func(*sbomSyft.SBOM) (io.Reader, error){
+					sbomFormatSyftJSON:      sbom.ToSyftJSON,
+					sbomFormatSPDXJSON:      sbom.ToSPDXJSON,
+					sbomFormatCycloneDXJSON: sbom.ToCycloneDXJSON,
+				}[p.outputFormat]
+
+				jsonReader, err := encode(s)
 				if err != nil {
 					return fmt.Errorf("failed to encode SBOM: %w", err)
 				}
@@ -75,6 +85,7 @@ func cmdSBOM() *cobra.Command {
 	}
 
 	p.addFlagsTo(cmd)
+	cmd.AddCommand(cmdSBOMCache())
 	return cmd
 }
 
@@ -85,7 +96,7 @@ type sbomParams struct {
 }
 
 func (p *sbomParams) addFlagsTo(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&p.outputFormat, "output", "o", sbomFormatOutline, "output format (outline, syft-json)")
+	cmd.Flags().StringVarP(&p.outputFormat, "output", "o", sbomFormatOutline, fmt.Sprintf("output format (%s)", strings.Join(validSBOMFormats, ", ")))
 	cmd.Flags().StringVar(&p.distro, "distro", "wolfi", "distro to report in SBOM")
 	cmd.Flags().BoolVar(&p.disableSBOMCache, "disable-sbom-cache", false, "don't use the SBOM cache")
 }
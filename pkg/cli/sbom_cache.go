@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/sbom"
+)
+
+func cmdSBOMCache() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "cache",
+		SilenceErrors: true,
+		Short:         "Manage the cache of generated SBOMs",
+		Long: `Manage the cache of generated SBOMs.
+
+Unless caching is disabled, each SBOM generated for an APK is cached, keyed by
+the APK's digest, the distro, and the version of the SBOM generation
+configuration. SBOMs cached by a version of wolfictl that generated SBOMs
+differently are stale: they're never used again, and they're always evicted
+when the cache is pruned.`,
+	}
+
+	cmd.AddCommand(
+		cmdSBOMCacheLs(),
+		cmdSBOMCachePrune(),
+		cmdSBOMCacheClear(),
+	)
+
+	return cmd
+}
+
+func cmdSBOMCacheLs() *cobra.Command {
+	var outputJSON bool
+	cmd := &cobra.Command{
+		Use:           "ls",
+		Short:         "list the cached SBOMs, with their sizes and when they were last used",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			entries, err := sbom.ListCache()
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				if entries == nil {
+					entries = []sbom.CacheEntry{}
+				}
+				return json.NewEncoder(os.Stdout).Encode(entries)
+			}
+
+			fmt.Println(renderSBOMCacheEntries(entries))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "print the cached SBOMs as JSON")
+	return cmd
+}
+
+func renderSBOMCacheEntries(entries []sbom.CacheEntry) string {
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+	summary := fmt.Sprintf("%d cached SBOMs (%s) in %s", len(entries), humanize.Bytes(uint64(total)), sbom.CacheDirectory())
+
+	if len(entries) == 0 {
+		return summary
+	}
+
+	sb := new(strings.Builder)
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "PATH\tSIZE\tLAST USED\t")
+	for _, e := range entries {
+		var stale string
+		if e.Stale {
+			stale = "(stale)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Path, humanize.Bytes(uint64(e.Size)), humanize.Time(e.LastUsed), stale)
+	}
+	_ = w.Flush()
+
+	return sb.String() + "\n" + summary
+}
+
+type sbomCachePruneParams struct {
+	maxAge  time.Duration
+	maxSize string
+}
+
+func cmdSBOMCachePrune() *cobra.Command {
+	p := &sbomCachePruneParams{}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "evict stale SBOMs, and SBOMs beyond the given age and size limits, from the cache",
+		Long: `Evict stale SBOMs, and SBOMs beyond the given age and size limits, from the cache.
+
+SBOMs that haven't been used for longer than --max-age are evicted. Then, if the
+cache is still larger than --max-size, the least recently used SBOMs are
+evicted until it fits.`,
+		Example: `
+# Keep at most 2 GB of SBOMs used in the last week
+wolfictl sbom cache prune --max-age 168h --max-size 2GB
+`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if p.maxAge < 0 {
+				return fmt.Errorf("--max-age must not be negative")
+			}
+
+			opts := sbom.PruneOptions{
+				MaxAge: p.maxAge,
+			}
+
+			if p.maxSize != "" {
+				size, err := humanize.ParseBytes(p.maxSize)
+				if err != nil {
+					return fmt.Errorf("invalid --max-size %q: %w", p.maxSize, err)
+				}
+				opts.MaxSize = int64(size)
+			}
+
+			evicted, err := sbom.PruneCache(opts)
+			if err != nil {
+				return err
+			}
+
+			var freed int64
+			for _, e := range evicted {
+				freed += e.Size
+			}
+			fmt.Fprintf(os.Stderr, "Evicted %d cached SBOMs (%s)\n", len(evicted), humanize.Bytes(uint64(freed)))
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+func (p *sbomCachePruneParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&p.maxAge, "max-age", 0, "evict SBOMs that haven't been used for longer than this (e.g. 168h)")
+	cmd.Flags().StringVar(&p.maxSize, "max-size", "", "evict the least recently used SBOMs until the cache is no larger than this (e.g. 2GB)")
+}
+
+func cmdSBOMCacheClear() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "clear",
+		Short:         "remove all SBOMs from the cache",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			err := sbom.ClearCache()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Cleared SBOM cache (%s)\n", sbom.CacheDirectory())
+			return nil
+		},
+	}
+
+	return cmd
+}
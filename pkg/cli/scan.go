@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	sbomSyft "github.com/anchore/syft/syft/sbom"
 	"github.com/charmbracelet/lipgloss"
@@ -21,15 +23,32 @@ import (
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/sbom"
 	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
 	"golang.org/x/exp/slices"
 )
 
 func cmdScan() *cobra.Command {
 	p := &scanParams{}
 	cmd := &cobra.Command{
-		Use:           "scan <path/to/package.apk> ...",
-		Short:         "Scan an apk file for vulnerabilities",
-		Args:          cobra.MinimumNArgs(1),
+		Use:   "scan { <path/to/package.apk | path/to/image> ... | --repo <dir-or-APKINDEX> }",
+		Short: "Scan an apk file or OCI image for vulnerabilities",
+		Long: `Scan an apk file or OCI image for vulnerabilities.
+
+Each input can be an APK, an OCI image layout directory, or an image tarball
+(either an archived OCI image layout or the output of 'docker save'). When
+scanning an image, each finding is attributed to the installed APK that owns
+the affected package, and advisory-based filtering uses that APK's advisories.
+
+With --repo, the latest version of every package in the given repository
+(a directory of APKs, or an APKINDEX alongside its APKs) is scanned using a
+pool of workers, and an aggregate report is produced.
+
+With --create-advisories, an advisory is created in the advisories repo for each
+vulnerability found that doesn't have an advisory yet. Its detection event
+records the matched component's name, version, type, and location. Add
+--interactive to choose the event for each advisory instead.`,
+		Args:          cobra.ArbitraryArgs,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if p.outputFormat == "" {
@@ -38,8 +57,34 @@ func cmdScan() *cobra.Command {
 
 			// Validate inputs
 
+			if p.repo == "" && len(args) == 0 {
+				return errors.New("requires at least 1 input, or --repo")
+			}
+			if p.repo != "" && len(args) > 0 {
+				return errors.New("inputs can't be specified when using --repo")
+			}
+			if p.workers < 1 {
+				return fmt.Errorf("invalid number of workers %d, must be at least 1", p.workers)
+			}
+
 			var advisoryCfgs *configs.Index[v2.Document]
 
+			if !slices.Contains(validSortOrders, p.sortBy) {
+				return fmt.Errorf(
+					"invalid sort order %q, must be one of [%s]",
+					p.sortBy,
+					strings.Join(validSortOrders, ", "),
+				)
+			}
+
+			exploitData, err := p.exploitability.load()
+			if err != nil {
+				return fmt.Errorf("failed to load exploitability data: %w", err)
+			}
+			if p.requireZeroKEV && exploitData == nil {
+				return errors.New("--require-zero-kev requires exploitability data, see --exploitability")
+			}
+
 			if !slices.Contains(validOutputFormats, p.outputFormat) {
 				return fmt.Errorf(
 					"invalid output format %q, must be one of [%s]",
@@ -60,37 +105,86 @@ func cmdScan() *cobra.Command {
 				if p.advisoriesRepoDir == "" {
 					return errors.New("advisory-based filtering requested, but no advisories repo dir was provided")
 				}
+			}
+
+			if p.explain && p.outputFormat != outputFormatOutline {
+				return fmt.Errorf("--explain is only supported with output format %q", outputFormatOutline)
+			}
 
+			if p.interactive && !p.createAdvisories {
+				return errors.New("--interactive requires --create-advisories")
+			}
+			if p.createAdvisories && p.advisoriesRepoDir == "" {
+				return errors.New("advisory creation requested, but no advisories repo dir was provided")
+			}
+
+			if p.advisoriesRepoDir != "" {
 				advisoriesFsys := rwos.DirFS(p.advisoriesRepoDir)
-				var err error
 				advisoryCfgs, err = v2.NewIndex(advisoriesFsys)
 				if err != nil {
 					return fmt.Errorf("failed to load advisory documents: %w", err)
 				}
 			}
 
+			if p.includeSuppressed && p.outputFormat != outputFormatOutline && p.outputFormat != outputFormatSARIF {
+				return fmt.Errorf("--include-suppressed is only supported with output formats %q and %q", outputFormatOutline, outputFormatSARIF)
+			}
+
+			var policy *scan.Policy
+			if p.policyFile != "" {
+				policy, err = scan.LoadPolicy(p.policyFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			var baselineFindings []*scan.Finding
+			if p.baseline != "" {
+				baselineFindings, err = loadBaselineFindings(p.baseline)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Load the vulnerability database once for all scans
+
+			scanner, err := scan.NewScanner(p.localDBFilePath)
+			if err != nil {
+				return err
+			}
+			defer scanner.Close()
+
+			if p.repo != "" {
+				return p.scanRepo(scanner, advisoryCfgs, exploitData, policy, baselineFindings)
+			}
+
 			// Do a scan for each arg
 
 			var scans []inputScan
+			var sarifInputs []scan.SARIFInput
 
 			for _, input := range args {
-				scannedInput, err := scanInput(input, p)
+				scannedInput, err := scanInput(input, p, scanner)
 				if err != nil {
 					return err
 				}
 
-				// If requested, filter scan results using advisories
-
-				if set := p.advisoryFilterSet; set != "" {
-					findings, err := scan.FilterWithAdvisories(scannedInput.Result, advisoryCfgs, set)
-					if err != nil {
-						return fmt.Errorf("failed to filter scan results with advisories during scan of %q: %w", input, err)
+				if p.createAdvisories {
+					if err := p.createMissingAdvisories(scannedInput.Result, advisoryCfgs); err != nil {
+						return err
 					}
+				}
 
-					scannedInput.Result.Findings = findings
+				suppressed, err := p.postProcess(scannedInput, advisoryCfgs, exploitData, policy)
+				if err != nil {
+					return err
 				}
 
 				scans = append(scans, *scannedInput)
+				sarifInputs = append(sarifInputs, scan.SARIFInput{
+					Result:     scannedInput.Result,
+					Suppressed: suppressed,
+				})
 
 				// Handle CLI options
 
@@ -100,34 +194,89 @@ func cmdScan() *cobra.Command {
 
 					if len(findings) == 0 {
 						fmt.Println("✅ No vulnerabilities found")
-					} else {
+					}
+					if len(findings) > 0 || len(suppressed) > 0 {
 						tree := newFindingsTree(findings)
+						tree.addSuppressed(suppressed)
+						tree.sortBy = p.sortBy
+						tree.explain = p.explain
 						fmt.Println(tree.render())
 					}
 				}
-				if p.requireZeroFindings && len(findings) > 0 {
+				if p.outputFormat == outputFormatMarkdown {
+					fmt.Println(renderResultMarkdown(scannedInput.Result, p.sortBy))
+				}
+				if p.outputFormat == outputFormatTable {
+					fmt.Println(renderResultTable(scannedInput.Result, p.sortBy))
+				}
+				if p.requireZeroFindings && p.baseline == "" && len(findings) > 0 {
 					// Exit with error immediately if any vulnerabilities are found
 					return fmt.Errorf("more than 0 vulnerabilities found")
 				}
+				if p.requireZeroKEV {
+					if kev := scan.KnownExploitedFindings(findings); len(kev) > 0 {
+						return fmt.Errorf("%d known exploited vulnerabilities found", len(kev))
+					}
+				}
+			}
+
+			var comparison *scan.Comparison
+			if p.baseline != "" {
+				results := lo.Map(scans, func(s inputScan, _ int) *scan.Result { return s.Result })
+				c := scan.Compare(baselineFindings, allFindings(results))
+				comparison = &c
+
+				if p.outputFormat == outputFormatOutline {
+					fmt.Println(renderComparison(c, p.sortBy))
+				}
 			}
 
 			if p.outputFormat == outputFormatJSON {
+				var output any = scans
+				if comparison != nil {
+					output = baselineOutput{
+						Scans:      scans,
+						Comparison: *comparison,
+					}
+				}
+
 				enc := json.NewEncoder(os.Stdout)
-				err := enc.Encode(scans)
+				err := enc.Encode(output)
 				if err != nil {
 					return fmt.Errorf("failed to marshal scans to JSON: %w", err)
 				}
 			}
 
+			if p.outputFormat == outputFormatSARIF {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				err := enc.Encode(scan.ToSARIF(sarifInputs))
+				if err != nil {
+					return fmt.Errorf("failed to marshal scans to SARIF: %w", err)
+				}
+			}
+
+			if p.requireZeroFindings && comparison != nil && len(comparison.New) > 0 {
+				return fmt.Errorf("%d new vulnerabilities found since baseline", len(comparison.New))
+			}
+
+			if policy != nil {
+				results := lo.Map(scans, func(s inputScan, _ int) *scan.Result { return s.Result })
+				if err := checkPolicy(policy, results); err != nil {
+					return err
+				}
+			}
+
 			return nil
 		},
 	}
 
 	p.addFlagsTo(cmd)
+	cmd.AddCommand(cmdScanDB())
 	return cmd
 }
 
-func scanInput(inputFilePath string, p *scanParams) (*inputScan, error) {
+func scanInput(inputFilePath string, p *scanParams, scanner *scan.Scanner) (*inputScan, error) {
 	if inputFilePath == "-" {
 		// Read stdin into a temp file.
 		t, err := os.CreateTemp("", "wolfictl-scan-")
@@ -177,14 +326,21 @@ func scanInput(inputFilePath string, p *scanParams) (*inputScan, error) {
 	}
 	defer inputFile.Close()
 
-	// Get SBOM of the APK
+	// Get SBOM of the APK (or image)
 
 	var apkSBOM io.Reader
 	if p.sbomInput {
 		apkSBOM = inputFile
 	} else {
+		imageFormat, err := sbom.DetectImageFormat(inputFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect input file: %w", err)
+		}
+
 		var s *sbomSyft.SBOM
-		if p.disableSBOMCache {
+		if imageFormat != sbom.ImageFormatNone {
+			s, err = sbom.GenerateFromImage(inputFilePath, p.distro)
+		} else if p.disableSBOMCache {
 			s, err = sbom.Generate(inputFilePath, inputFile, p.distro)
 		} else {
 			s, err = sbom.CachedGenerate(inputFilePath, inputFile, p.distro)
@@ -202,7 +358,7 @@ func scanInput(inputFilePath string, p *scanParams) (*inputScan, error) {
 
 	// Do vulnerability scan!
 
-	result, err := scan.APKSBOM(apkSBOM, p.localDBFilePath)
+	result, err := scanner.APKSBOM(apkSBOM)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan APK using %q: %w", inputFilePath, err)
 	}
@@ -215,6 +371,69 @@ func scanInput(inputFilePath string, p *scanParams) (*inputScan, error) {
 	return is, nil
 }
 
+// postProcess applies the severity ratings from advisories, filters the scan
+// results using advisories and then the scan policy, and enriches them with
+// exploitability data, as requested. Findings filtered out by advisories are
+// kept in the result as suppressed findings. It returns the suppressed findings
+// if the user wants to include them in the output.
+func (p *scanParams) postProcess(scannedInput *inputScan, advisoryCfgs *configs.Index[v2.Document], exploitData *exploit.Data, policy *scan.Policy) ([]scan.SuppressedFinding, error) {
+	// Show the distro's own severity ratings wherever advisories have them,
+	// whether or not findings are filtered
+
+	if advisoryCfgs != nil {
+		if err := scan.ApplySeverityOverrides(scannedInput.Result, advisoryCfgs); err != nil {
+			return nil, fmt.Errorf("failed to apply severity overrides during scan of %q: %w", scannedInput.InputFile, err)
+		}
+	}
+
+	// If requested, filter scan results using advisories
+
+	if set := p.advisoryFilterSet; set != "" {
+		err := scan.SuppressWithAdvisories(scannedInput.Result, advisoryCfgs, set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter scan results with advisories during scan of %q: %w", scannedInput.InputFile, err)
+		}
+	}
+
+	// Apply the policy's ignore rules after advisory-based filtering
+
+	if policy != nil {
+		scannedInput.Result.Findings = policy.Filter(scannedInput.Result.Findings, time.Now())
+	}
+
+	if exploitData != nil {
+		scan.EnrichWithExploitability(scannedInput.Result.Findings, exploitData)
+	}
+
+	if !p.includeSuppressed {
+		return nil, nil
+	}
+
+	return scannedInput.Result.Suppressed, nil
+}
+
+// checkPolicy returns an error if any of the findings in the results violate the
+// policy, after reporting each violation.
+func checkPolicy(policy *scan.Policy, results []*scan.Result) error {
+	violations := policy.Violations(allFindings(results), time.Now())
+	if len(violations) == 0 {
+		return nil
+	}
+
+	for _, v := range violations {
+		fmt.Fprintf(
+			os.Stderr,
+			"❌ %s in %s %s: %s\n",
+			v.Finding.Vulnerability.ID,
+			v.Finding.Package.Name,
+			v.Finding.Package.Version,
+			v.Reason,
+		)
+	}
+
+	return fmt.Errorf("%d findings violate the scan policy", len(violations))
+}
+
 type scanParams struct {
 	requireZeroFindings bool
 	localDBFilePath     string
@@ -224,25 +443,50 @@ type scanParams struct {
 	advisoryFilterSet   string
 	advisoriesRepoDir   string
 	disableSBOMCache    bool
+	exploitability      exploitabilityParams
+	requireZeroKEV      bool
+	sortBy              string
+	includeSuppressed   bool
+	repo                string
+	workers             int
+	baseline            string
+	policyFile          string
+	createAdvisories    bool
+	interactive         bool
+	explain             bool
 }
 
 func (p *scanParams) addFlagsTo(cmd *cobra.Command) {
-	cmd.Flags().BoolVar(&p.requireZeroFindings, "require-zero", false, "exit 1 if any vulnerabilities are found")
+	cmd.Flags().BoolVar(&p.requireZeroFindings, "require-zero", false, "exit 1 if any vulnerabilities are found (only new vulnerabilities, when using --baseline)")
 	cmd.Flags().StringVar(&p.localDBFilePath, "local-file-grype-db", "", "import a local grype db file")
 	cmd.Flags().StringVarP(&p.outputFormat, "output", "o", "", fmt.Sprintf("output format (%s), defaults to %s", strings.Join(validOutputFormats, "|"), outputFormatOutline))
 	cmd.Flags().BoolVarP(&p.sbomInput, "sbom", "s", false, "treat input(s) as SBOM(s) of APK(s) instead of as actual APK(s)")
 	cmd.Flags().StringVar(&p.distro, "distro", "wolfi", "distro to use during vulnerability matching")
 	cmd.Flags().StringVarP(&p.advisoryFilterSet, "advisory-filter", "f", "", fmt.Sprintf("exclude vulnerability matches that are referenced from the specified set of advisories (%s)", strings.Join(scan.ValidAdvisoriesSets, "|")))
-	cmd.Flags().StringVarP(&p.advisoriesRepoDir, "advisories-repo-dir", "a", "", "local directory for advisory data")
+	cmd.Flags().StringVarP(&p.advisoriesRepoDir, "advisories-repo-dir", "a", "", "local directory for advisory data, whose severity ratings for true positives replace the scanner's")
 	cmd.Flags().BoolVar(&p.disableSBOMCache, "disable-sbom-cache", false, "don't use the SBOM cache")
+	p.exploitability.addFlagsTo(cmd)
+	cmd.Flags().BoolVar(&p.requireZeroKEV, "require-zero-kev", false, "exit 1 if any known exploited vulnerabilities (per the KEV catalog) are found")
+	addSortFlag(&p.sortBy, cmd)
+	cmd.Flags().StringVar(&p.repo, "repo", "", "scan the latest version of every package in the given repository (a directory of APKs, or an APKINDEX)")
+	cmd.Flags().IntVarP(&p.workers, "workers", "j", runtime.NumCPU(), "number of packages to scan concurrently when using --repo")
+	cmd.Flags().StringVar(&p.baseline, "baseline", "", "JSON output of an earlier scan to compare findings with, classifying them as new, fixed, or unchanged")
+	cmd.Flags().StringVar(&p.policyFile, "policy", "", "policy YAML file with a fail-on severity and expiring ignore rules, applied after advisory-based filtering")
+	cmd.Flags().BoolVar(&p.includeSuppressed, "include-suppressed", false, fmt.Sprintf("show findings filtered out by advisories, dimmed in %s output and as suppressed results in %s output (%s output always includes them)", outputFormatOutline, outputFormatSARIF, outputFormatJSON))
+	cmd.Flags().BoolVar(&p.createAdvisories, "create-advisories", false, "create an advisory with a detection event for each vulnerability found that doesn't have an advisory yet (requires --advisories-repo-dir)")
+	cmd.Flags().BoolVar(&p.interactive, "interactive", false, "with --create-advisories, prompt for the event to record in each new advisory")
+	cmd.Flags().BoolVar(&p.explain, "explain", false, fmt.Sprintf("show how each vulnerability was matched, e.g. by CPE or by exact package match (%s output only)", outputFormatOutline))
 }
 
 const (
-	outputFormatOutline = "outline"
-	outputFormatJSON    = "json"
+	outputFormatOutline  = "outline"
+	outputFormatJSON     = "json"
+	outputFormatSARIF    = "sarif"
+	outputFormatMarkdown = "markdown"
+	outputFormatTable    = "table"
 )
 
-var validOutputFormats = []string{outputFormatOutline, outputFormatJSON}
+var validOutputFormats = []string{outputFormatOutline, outputFormatJSON, outputFormatSARIF, outputFormatMarkdown, outputFormatTable}
 
 type inputScan struct {
 	InputFile string
@@ -252,34 +496,96 @@ type inputScan struct {
 type findingsTree struct {
 	findingsByPackageByLocation map[string]map[string][]*scan.Finding
 	packagesByID                map[string]scan.Package
+
+	// apksByPackageID is the installed APK that owns each package, for findings
+	// from an image scan.
+	apksByPackageID map[string]*scan.TargetAPK
+
+	// suppressed holds the advisory data for the findings in the tree that were
+	// filtered out by advisories, which are rendered dimmed.
+	suppressed map[*scan.Finding]scan.SuppressedFinding
+
+	// sortBy is the order in which findings are listed for each package.
+	sortBy string
+
+	// explain is true if each finding should be followed by an explanation of
+	// how it was matched.
+	explain bool
 }
 
 func newFindingsTree(findings []*scan.Finding) *findingsTree {
-	tree := make(map[string]map[string][]*scan.Finding)
-	packagesByID := make(map[string]scan.Package)
+	t := &findingsTree{
+		findingsByPackageByLocation: make(map[string]map[string][]*scan.Finding),
+		packagesByID:                make(map[string]scan.Package),
+		apksByPackageID:             make(map[string]*scan.TargetAPK),
+		suppressed:                  make(map[*scan.Finding]scan.SuppressedFinding),
+	}
 
 	for _, f := range findings {
-		loc := f.Package.Location
-		packageID := f.Package.ID
-		packagesByID[packageID] = f.Package
+		t.add(f)
+	}
 
-		if _, ok := tree[loc]; !ok {
-			tree[loc] = make(map[string][]*scan.Finding)
-		}
+	return t
+}
 
-		tree[loc][packageID] = append(tree[loc][packageID], f)
+// addSuppressed adds findings that were filtered out by advisories to the tree.
+func (t *findingsTree) addSuppressed(suppressed []scan.SuppressedFinding) {
+	for _, s := range suppressed {
+		f := s.Finding
+		t.add(&f)
+		t.suppressed[&f] = s
+	}
+}
+
+func (t *findingsTree) add(f *scan.Finding) {
+	loc := f.Package.Location
+	packageID := f.Package.ID
+	t.packagesByID[packageID] = f.Package
+	if f.APK != nil {
+		t.apksByPackageID[packageID] = f.APK
 	}
 
-	return &findingsTree{
-		findingsByPackageByLocation: tree,
-		packagesByID:                packagesByID,
+	if _, ok := t.findingsByPackageByLocation[loc]; !ok {
+		t.findingsByPackageByLocation[loc] = make(map[string][]*scan.Finding)
 	}
+
+	t.findingsByPackageByLocation[loc][packageID] = append(t.findingsByPackageByLocation[loc][packageID], f)
 }
 
-func (t findingsTree) render() string {
+// locations returns the locations in the tree, sorted.
+func (t findingsTree) locations() []string {
 	locations := lo.Keys(t.findingsByPackageByLocation)
 	sort.Strings(locations)
 
+	return locations
+}
+
+// packagesAt returns the packages at the given location, sorted by name.
+func (t findingsTree) packagesAt(location string) []scan.Package {
+	packageIDs := lo.Keys(t.findingsByPackageByLocation[location])
+	packages := lo.Map(packageIDs, func(id string, _ int) scan.Package {
+		return t.packagesByID[id]
+	})
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+
+	return packages
+}
+
+// findingsFor returns the findings for the given package at the given location,
+// in the tree's sort order.
+func (t findingsTree) findingsFor(location string, pkg scan.Package) []*scan.Finding {
+	findings := t.findingsByPackageByLocation[location][pkg.ID]
+	sortFindings(findings, t.sortBy)
+
+	return findings
+}
+
+func (t findingsTree) render() string {
+	locations := t.locations()
+
 	var lines []string
 	for i, location := range locations {
 		var treeStem, verticalLine string
@@ -294,39 +600,42 @@ func (t findingsTree) render() string {
 		line := treeStem + fmt.Sprintf("📄 %s", location)
 		lines = append(lines, line)
 
-		packageIDs := lo.Keys(t.findingsByPackageByLocation[location])
-		packages := lo.Map(packageIDs, func(id string, _ int) scan.Package {
-			return t.packagesByID[id]
-		})
-
-		sort.SliceStable(packages, func(i, j int) bool {
-			return packages[i].Name < packages[j].Name
-		})
-
-		for _, pkg := range packages {
+		for _, pkg := range t.packagesAt(location) {
 			line := fmt.Sprintf(
 				"%s       📦 %s %s %s",
 				verticalLine,
 				pkg.Name,
 				pkg.Version,
-				styleSubtle.Render("("+pkg.Type+")"),
+				styleSubtle.Render("("+t.renderPackageOrigin(pkg)+")"),
 			)
 			lines = append(lines, line)
 
-			findings := t.findingsByPackageByLocation[location][pkg.ID]
-			sort.SliceStable(findings, func(i, j int) bool {
-				return findings[i].Vulnerability.ID < findings[j].Vulnerability.ID
-			})
+			for _, f := range t.findingsFor(location, pkg) {
+				if s, ok := t.suppressed[f]; ok {
+					lines = append(lines, fmt.Sprintf(
+						"%s           %s",
+						verticalLine,
+						renderSuppressedFinding(s),
+					))
+					continue
+				}
 
-			for _, f := range findings {
 				line := fmt.Sprintf(
-					"%s           %s %s%s",
+					"%s           %s%s %s%s%s",
 					verticalLine,
 					renderSeverity(f.Vulnerability.Severity),
+					renderCVSSScore(f.Vulnerability),
 					renderVulnerabilityID(f.Vulnerability),
+					renderExploitability(f.Vulnerability.Exploitability),
 					renderFixedIn(f.Vulnerability),
 				)
 				lines = append(lines, line)
+
+				if t.explain && f.Match != nil {
+					for _, explanation := range renderMatchExplanation(*f.Match) {
+						lines = append(lines, fmt.Sprintf("%s               %s", verticalLine, explanation))
+					}
+				}
 			}
 		}
 
@@ -336,6 +645,77 @@ func (t findingsTree) render() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderMatchExplanation renders how a vulnerability was matched to a package,
+// as one or more lines.
+func renderMatchExplanation(m scan.Match) []string {
+	var lines []string
+	for _, d := range m.Details {
+		lines = append(lines, styleSubtle.Render(fmt.Sprintf("↳ %s by %s in %s", d.Type, d.Matcher, m.Namespace)))
+
+		if searchedBy := renderMatchData(d.SearchedBy); searchedBy != "" {
+			lines = append(lines, styleSubtle.Render("  searched by: "+searchedBy))
+		}
+		if foundBy := renderMatchData(d.FoundBy); foundBy != "" {
+			lines = append(lines, styleSubtle.Render("  found by: "+foundBy))
+		}
+	}
+
+	return lines
+}
+
+func renderMatchData(data any) string {
+	if data == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("%v", data)
+	}
+
+	return string(b)
+}
+
+// renderSuppressedFinding renders a finding that was filtered out by an
+// advisory, dimmed, along with the reason it was filtered out.
+func renderSuppressedFinding(s scan.SuppressedFinding) string {
+	v := s.Vulnerability
+	id := vuln.PreferredID(append([]string{v.ID}, v.Aliases...)...)
+
+	return styleSubtle.Render(fmt.Sprintf(
+		"%s %s suppressed by %s: %s",
+		v.Severity,
+		id,
+		s.AdvisoryID,
+		s.Justification,
+	))
+}
+
+func (t findingsTree) renderPackageOrigin(pkg scan.Package) string {
+	apk, ok := t.apksByPackageID[pkg.ID]
+	if !ok || pkg.Type == "apk" {
+		return pkg.Type
+	}
+
+	return fmt.Sprintf("%s, from %s-%s", pkg.Type, apk.Name, apk.Version)
+}
+
+func sortFindings(findings []*scan.Finding, sortBy string) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if sortBy == sortByExploitability {
+			a, b := findings[i].Vulnerability.Exploitability, findings[j].Vulnerability.Exploitability
+			if b.Less(a) {
+				return true
+			}
+			if a.Less(b) {
+				return false
+			}
+		}
+
+		return findings[i].Vulnerability.ID < findings[j].Vulnerability.ID
+	})
+}
+
 func renderSeverity(severity string) string {
 	switch severity {
 	case "Negligible":
@@ -353,25 +733,27 @@ func renderSeverity(severity string) string {
 	}
 }
 
-func renderVulnerabilityID(vuln scan.Vulnerability) string {
-	var cveID string
-
-	for _, alias := range vuln.Aliases {
-		if strings.HasPrefix(alias, "CVE-") {
-			cveID = alias
-			break
-		}
+func renderCVSSScore(v scan.Vulnerability) string {
+	score, ok := v.HighestCVSSBaseScore()
+	if !ok {
+		return ""
 	}
 
-	if cveID == "" {
-		return hyperlinkVulnerabilityID(vuln.ID)
+	return styleSubtle.Render(fmt.Sprintf(" (CVSS %.1f)", score))
+}
+
+func renderVulnerabilityID(v scan.Vulnerability) string {
+	primaryID := vuln.PreferredID(append([]string{v.ID}, v.Aliases...)...)
+
+	if primaryID == v.ID {
+		return hyperlinkVulnerabilityID(v.ID)
 	}
 
 	return fmt.Sprintf(
 		"%s %s",
-		hyperlinkVulnerabilityID(cveID),
+		hyperlinkVulnerabilityID(primaryID),
 
-		styleSubtle.Render(hyperlinkVulnerabilityID(vuln.ID)),
+		styleSubtle.Render(hyperlinkVulnerabilityID(v.ID)),
 	)
 }
 
@@ -382,12 +764,8 @@ func hyperlinkVulnerabilityID(id string) string {
 		return id
 	}
 
-	switch {
-	case strings.HasPrefix(id, "CVE-"):
-		return termlink.Link(id, fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%s", id))
-
-	case strings.HasPrefix(id, "GHSA-"):
-		return termlink.Link(id, fmt.Sprintf("https://github.com/advisories/%s", id))
+	if u := vuln.URL(id); u != "" {
+		return termlink.Link(id, u)
 	}
 
 	return id
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/cli/components/advisory/prompt"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// createMissingAdvisories creates an advisory for each vulnerability found in
+// the scan result that doesn't have an advisory yet. Each advisory starts with
+// a scan-based detection event, unless the user is prompted and chooses a
+// different event.
+func (p *scanParams) createMissingAdvisories(result *scan.Result, advisoryCfgs *configs.Index[v2.Document]) error {
+	reqs, err := scan.AdvisoryRequests(result, advisoryCfgs)
+	if err != nil {
+		return fmt.Errorf("failed to determine advisories to create: %w", err)
+	}
+
+	for _, req := range reqs {
+		if p.interactive {
+			req, err = promptForScanAdvisory(req)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := advisory.Create(req, advisory.CreateOptions{
+			AdvisoryDocs: advisoryCfgs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create advisory %s for %s: %w", req.VulnerabilityID, req.Package, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "📝 Created advisory %s for %s (%s)\n", req.VulnerabilityID, req.Package, req.Event.Type)
+	}
+
+	return nil
+}
+
+// promptForScanAdvisory asks the user which event to record for the advisory
+// request. If the user chooses a detection event, the request's scan-based
+// detection is used.
+func promptForScanAdvisory(req advisory.Request) (advisory.Request, error) {
+	var detection *v2.Detection
+	if d, ok := req.Event.Data.(v2.Detection); ok {
+		detection = &d
+		fmt.Fprintf(os.Stderr, "\n%s: %s %s\n", req.Package, req.VulnerabilityID, styleSubtle.Render(renderScanDetection(d)))
+	} else {
+		fmt.Fprintf(os.Stderr, "\n%s: %s\n", req.Package, req.VulnerabilityID)
+	}
+
+	req.Event = v2.Event{
+		Timestamp: req.Event.Timestamp,
+	}
+
+	m := prompt.New(prompt.Configuration{
+		Request:                    req,
+		AllowedPackagesFunc:        func() []string { return nil },
+		AllowedVulnerabilitiesFunc: func(string) []string { return nil },
+		AllowedFixedVersionsFunc:   func(string) []string { return nil },
+		Detection:                  detection,
+	})
+
+	returnedModel, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return advisory.Request{}, err
+	}
+
+	m, ok := returnedModel.(prompt.Model)
+	if !ok {
+		return advisory.Request{}, fmt.Errorf("unexpected model type: %T", returnedModel)
+	}
+	if m.EarlyExit {
+		return advisory.Request{}, errors.New("advisory creation canceled")
+	}
+
+	return m.Request, nil
+}
+
+func renderScanDetection(d v2.Detection) string {
+	data, ok := d.Data.(v2.DetectionScanV1)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("(%s %s at %s)", data.ComponentName, data.ComponentVersion, data.ComponentLocation)
+}
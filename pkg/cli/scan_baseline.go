@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// baselineOutput is the JSON output of a scan that was compared with a
+// baseline.
+type baselineOutput struct {
+	Scans      []inputScan
+	Comparison scan.Comparison
+}
+
+// loadBaselineFindings reads the findings from the JSON output of an earlier
+// scan. It accepts the output of a regular scan, of a scan compared with a
+// baseline, and of a repository scan.
+func loadBaselineFindings(baselinePath string) ([]*scan.Finding, error) {
+	b, err := os.ReadFile(baselinePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var results []*scan.Result
+
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		var scans []inputScan
+		if err := json.Unmarshal(trimmed, &scans); err != nil {
+			return nil, fmt.Errorf("failed to decode baseline %q: %w", baselinePath, err)
+		}
+
+		for _, s := range scans {
+			results = append(results, s.Result)
+		}
+	} else {
+		var doc struct {
+			// Scans is set for the output of a scan compared with a baseline.
+			Scans []inputScan
+
+			// Results is set for the output of a repository scan.
+			Results []*scan.Result
+		}
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode baseline %q: %w", baselinePath, err)
+		}
+
+		for _, s := range doc.Scans {
+			results = append(results, s.Result)
+		}
+		results = append(results, doc.Results...)
+	}
+
+	return allFindings(results), nil
+}
+
+func allFindings(results []*scan.Result) []*scan.Finding {
+	var findings []*scan.Finding
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		findings = append(findings, r.Findings...)
+	}
+
+	return findings
+}
+
+func renderComparison(c scan.Comparison, sortBy string) string {
+	lines := []string{
+		fmt.Sprintf(
+			"Compared with baseline: %d new, %d fixed, %d unchanged",
+			len(c.New),
+			len(c.Fixed),
+			len(c.Unchanged),
+		),
+	}
+
+	for _, section := range []struct {
+		title    string
+		findings []*scan.Finding
+	}{
+		{title: "🆕 New", findings: c.New},
+		{title: "🎉 Fixed", findings: c.Fixed},
+	} {
+		if len(section.findings) == 0 {
+			continue
+		}
+
+		tree := newFindingsTree(section.findings)
+		tree.sortBy = sortBy
+		lines = append(lines, "", section.title, tree.render())
+	}
+
+	return strings.Join(lines, "\n")
+}
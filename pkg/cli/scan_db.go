@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func cmdScanDB() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "db",
+		SilenceErrors: true,
+		Short:         "Manage the vulnerability database used for scans",
+		Long: `Manage the vulnerability database used for scans.
+
+By default, scans update the cached vulnerability database whenever it's more
+than a day old. To make scans reproducible, or to scan offline, import or
+update a database once and then pin it: while a database is pinned, scans use
+it regardless of its age and never update it. Scan results record which
+database was used.`,
+	}
+
+	cmd.AddCommand(
+		cmdScanDBStatus(),
+		cmdScanDBUpdate(),
+		cmdScanDBImport(),
+		cmdScanDBPin(),
+	)
+
+	return cmd
+}
+
+func cmdScanDBStatus() *cobra.Command {
+	var outputJSON bool
+	cmd := &cobra.Command{
+		Use:           "status",
+		Short:         "show the build date, schema, and validity of the cached vulnerability database",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			status, err := scan.GetDBStatus()
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				return json.NewEncoder(os.Stdout).Encode(status)
+			}
+
+			fmt.Println(renderDBStatus(status))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "print the status as JSON")
+	return cmd
+}
+
+func renderDBStatus(status *scan.DBStatus) string {
+	lines := []string{
+		fmt.Sprintf("Location: %s", status.Location),
+		fmt.Sprintf("Built: %s", renderDBTime(status.Built)),
+		fmt.Sprintf("Schema: %d", status.SchemaVersion),
+		fmt.Sprintf("Checksum: %s", status.Checksum),
+	}
+
+	if status.Pin != nil {
+		lines = append(lines, fmt.Sprintf(
+			"Pinned: yes %s",
+			styleSubtle.Render(fmt.Sprintf("(%s, pinned %s)", status.Pin.Checksum, renderDBTime(status.Pin.PinnedAt))),
+		))
+	} else {
+		lines = append(lines, "Pinned: no")
+	}
+
+	if status.Err != "" {
+		lines = append(lines, fmt.Sprintf("Status: ❌ %s", status.Err))
+	} else {
+		lines = append(lines, "Status: ✅ valid")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderDBTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func cmdScanDBUpdate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "update",
+		Short:         "download the latest vulnerability database, if the cached one is out of date",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			updated, err := scan.UpdateDB()
+			if err != nil {
+				return err
+			}
+
+			if updated {
+				fmt.Fprintln(os.Stderr, "Vulnerability database updated")
+			} else {
+				fmt.Fprintln(os.Stderr, "Vulnerability database already up to date")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func cmdScanDBImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "import <path/to/db.tar.gz>",
+		Short:         "import a vulnerability database archive into the cache",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := scan.ImportDB(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Imported vulnerability database from %s\n", args[0])
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func cmdScanDBPin() *cobra.Command {
+	var remove bool
+	cmd := &cobra.Command{
+		Use:           "pin",
+		Short:         "pin the cached vulnerability database, so scans use it without updating it",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if remove {
+				if err := scan.UnpinDB(); err != nil {
+					return err
+				}
+
+				fmt.Fprintln(os.Stderr, "Vulnerability database unpinned")
+				return nil
+			}
+
+			pin, err := scan.PinDB()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Pinned vulnerability database %s (built %s)\n", pin.Checksum, renderDBTime(pin.Built))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "remove the pin, so that scans update the database again")
+	return cmd
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+// renderMarkdown renders the findings as GitHub-flavored Markdown, suitable for
+// a pull request comment: a summary line, followed by a collapsible section
+// with a table of findings for each location.
+func (t findingsTree) renderMarkdown() string {
+	lines := []string{fmt.Sprintf("**%s**", renderFindingsSummary(t.count()))}
+
+	for _, location := range t.locations() {
+		var rows []string
+		for _, pkg := range t.packagesAt(location) {
+			for _, f := range t.findingsFor(location, pkg) {
+				rows = append(rows, fmt.Sprintf(
+					"| %s | %s | %s | %s | %s |",
+					escapeMarkdownCell(pkg.Name),
+					escapeMarkdownCell(pkg.Version),
+					renderMarkdownVulnerabilityID(f.Vulnerability),
+					f.Vulnerability.Severity,
+					escapeMarkdownCell(f.Vulnerability.FixedVersion),
+				))
+			}
+		}
+
+		lines = append(lines,
+			"",
+			"<details>",
+			fmt.Sprintf("<summary><code>%s</code> (%d)</summary>", html.EscapeString(location), len(rows)),
+			"",
+			"| Package | Version | Vulnerability | Severity | Fixed in |",
+			"| --- | --- | --- | --- | --- |",
+		)
+		lines = append(lines, rows...)
+		lines = append(lines, "", "</details>")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderTable renders the findings as a plain text table, without any emoji,
+// colors, or hyperlinks, followed by a summary line.
+func (t findingsTree) renderTable() string {
+	counts := t.count()
+	if len(counts) == 0 {
+		return renderFindingsSummary(counts)
+	}
+
+	sb := new(strings.Builder)
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "LOCATION\tPACKAGE\tVERSION\tTYPE\tVULNERABILITY\tSEVERITY\tFIXED IN")
+	for _, location := range t.locations() {
+		for _, pkg := range t.packagesAt(location) {
+			for _, f := range t.findingsFor(location, pkg) {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					location,
+					pkg.Name,
+					pkg.Version,
+					pkg.Type,
+					preferredVulnerabilityID(f.Vulnerability),
+					f.Vulnerability.Severity,
+					f.Vulnerability.FixedVersion,
+				)
+			}
+		}
+	}
+	_ = w.Flush()
+
+	return sb.String() + "\n" + renderFindingsSummary(counts)
+}
+
+// count returns the number of findings in the tree, by severity.
+func (t findingsTree) count() map[string]int {
+	counts := make(map[string]int)
+	for _, byPackage := range t.findingsByPackageByLocation {
+		for _, findings := range byPackage {
+			for _, f := range findings {
+				counts[f.Vulnerability.Severity]++
+			}
+		}
+	}
+
+	return counts
+}
+
+func renderFindingsSummary(counts map[string]int) string {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+
+	if total == 0 {
+		return "No vulnerabilities found"
+	}
+
+	noun := "vulnerabilities"
+	if total == 1 {
+		noun = "vulnerability"
+	}
+
+	return fmt.Sprintf("%d %s found (%s)", total, noun, renderSeverityCounts(counts))
+}
+
+func preferredVulnerabilityID(v scan.Vulnerability) string {
+	return vuln.PreferredID(append([]string{v.ID}, v.Aliases...)...)
+}
+
+func renderMarkdownVulnerabilityID(v scan.Vulnerability) string {
+	id := preferredVulnerabilityID(v)
+	if u := vuln.URL(id); u != "" {
+		return fmt.Sprintf("[%s](%s)", id, u)
+	}
+
+	return escapeMarkdownCell(id)
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// renderResultMarkdown renders the scan result as a Markdown section, titled
+// with the scanned APK or image.
+func renderResultMarkdown(result *scan.Result, sortBy string) string {
+	tree := newFindingsTree(result.Findings)
+	tree.sortBy = sortBy
+
+	return fmt.Sprintf("### %s\n\n%s\n", escapeMarkdownCell(renderScanTarget(result)), tree.renderMarkdown())
+}
+
+// renderResultTable renders the scan result as a plain text table, preceded by
+// the scanned APK or image.
+func renderResultTable(result *scan.Result, sortBy string) string {
+	tree := newFindingsTree(result.Findings)
+	tree.sortBy = sortBy
+
+	return fmt.Sprintf("%s\n\n%s\n", renderScanTarget(result), tree.renderTable())
+}
+
+func renderScanTarget(result *scan.Result) string {
+	if result.TargetImage != nil {
+		return result.TargetImage.Name
+	}
+
+	return fmt.Sprintf("%s-%s", result.TargetAPK.Name, result.TargetAPK.Version)
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
+	"golang.org/x/sync/errgroup"
+)
+
+// severityOrder is the order in which severities are listed in reports, from
+// most to least severe.
+var severityOrder = []string{"Critical", "High", "Medium", "Low", "Negligible", "Unknown"}
+
+func (p *scanParams) scanRepo(scanner *scan.Scanner, advisoryCfgs *configs.Index[v2.Document], exploitData *exploit.Data, policy *scan.Policy, baselineFindings []*scan.Finding) error {
+	apks, err := scan.LatestRepoAPKs(p.repo)
+	if err != nil {
+		return fmt.Errorf("failed to list packages in repository %q: %w", p.repo, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Scanning %d packages from %s using %d workers\n", len(apks), p.repo, p.workers)
+
+	results := make([]*scan.Result, len(apks))
+	sarifInputs := make([]scan.SARIFInput, len(apks))
+
+	// Advisory creation writes to the advisory index, which isn't safe for
+	// concurrent use, and may prompt the user, so it's done one package at a time.
+	var advisoryMu sync.Mutex
+
+	var g errgroup.Group
+	g.SetLimit(p.workers)
+
+	for i, apk := range apks {
+		i, apk := i, apk
+		g.Go(func() error {
+			scannedInput, err := scanInput(apk.Path, p, scanner)
+			if err != nil {
+				return err
+			}
+
+			if p.createAdvisories {
+				advisoryMu.Lock()
+				defer advisoryMu.Unlock()
+
+				if err := p.createMissingAdvisories(scannedInput.Result, advisoryCfgs); err != nil {
+					return err
+				}
+			}
+
+			suppressed, err := p.postProcess(scannedInput, advisoryCfgs, exploitData, policy)
+			if err != nil {
+				return err
+			}
+
+			results[i] = scannedInput.Result
+			sarifInputs[i] = scan.SARIFInput{
+				Result:     scannedInput.Result,
+				Suppressed: suppressed,
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	report := scan.NewRepoReport(results, advisoryCfgs)
+	if p.baseline != "" {
+		c := scan.Compare(baselineFindings, allFindings(results))
+		report.Comparison = &c
+	}
+
+	switch p.outputFormat {
+	case outputFormatOutline:
+		fmt.Println(renderRepoReport(report))
+		if report.Comparison != nil {
+			fmt.Println()
+			fmt.Println(renderComparison(*report.Comparison, p.sortBy))
+		}
+
+	case outputFormatMarkdown:
+		fmt.Printf("## %s\n\n", renderFindingsSummary(report.TotalsBySeverity))
+		for _, result := range report.Results {
+			if len(result.Findings) > 0 {
+				fmt.Println(renderResultMarkdown(result, p.sortBy))
+			}
+		}
+
+	case outputFormatTable:
+		for _, result := range report.Results {
+			if len(result.Findings) > 0 {
+				fmt.Println(renderResultTable(result, p.sortBy))
+			}
+		}
+		fmt.Printf("Total: %s\n", renderFindingsSummary(report.TotalsBySeverity))
+
+	case outputFormatJSON:
+		enc := json.NewEncoder(os.Stdout)
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to marshal report to JSON: %w", err)
+		}
+
+	case outputFormatSARIF:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(scan.ToSARIF(sarifInputs)); err != nil {
+			return fmt.Errorf("failed to marshal report to SARIF: %w", err)
+		}
+	}
+
+	if p.requireZeroFindings {
+		if report.Comparison != nil {
+			if n := len(report.Comparison.New); n > 0 {
+				return fmt.Errorf("%d new vulnerabilities found since baseline", n)
+			}
+		} else if n := report.TotalFindings(); n > 0 {
+			return fmt.Errorf("%d vulnerabilities found", n)
+		}
+	}
+	if p.requireZeroKEV {
+		kev := 0
+		for _, result := range results {
+			kev += len(scan.KnownExploitedFindings(result.Findings))
+		}
+		if kev > 0 {
+			return fmt.Errorf("%d known exploited vulnerabilities found", kev)
+		}
+	}
+
+	if policy != nil {
+		return checkPolicy(policy, results)
+	}
+
+	return nil
+}
+
+func renderRepoReport(report *scan.RepoReport) string {
+	var lines []string
+
+	lines = append(lines, "📦 Findings by package")
+	withFindings := lo.Filter(report.Results, func(r *scan.Result, _ int) bool {
+		return len(r.Findings) > 0
+	})
+	if len(withFindings) == 0 {
+		lines = append(lines, "   ✅ No vulnerabilities found")
+	}
+	for _, result := range withFindings {
+		counts := make(map[string]int)
+		for _, f := range result.Findings {
+			counts[f.Vulnerability.Severity]++
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"   %s %s: %d %s",
+			result.TargetAPK.Name,
+			styleSubtle.Render(result.TargetAPK.Version),
+			len(result.Findings),
+			styleSubtle.Render("("+renderSeverityCounts(counts)+")"),
+		))
+	}
+
+	lines = append(lines, "", "📊 Totals by severity")
+	for _, severity := range sortedSeverities(report.TotalsBySeverity) {
+		lines = append(lines, fmt.Sprintf("   %s: %d", renderSeverity(severity), report.TotalsBySeverity[severity]))
+	}
+	lines = append(lines, fmt.Sprintf("   Total: %d", report.TotalFindings()))
+
+	if len(report.UnresolvedAdvisories) > 0 {
+		lines = append(lines, "", "🔎 Packages with unresolved advisories")
+
+		packages := lo.Keys(report.UnresolvedAdvisories)
+		sort.Strings(packages)
+		for _, name := range packages {
+			lines = append(lines, fmt.Sprintf("   %s: %s", name, strings.Join(report.UnresolvedAdvisories[name], ", ")))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderSeverityCounts(counts map[string]int) string {
+	parts := lo.Map(sortedSeverities(counts), func(severity string, _ int) string {
+		return fmt.Sprintf("%d %s", counts[severity], severity)
+	})
+
+	return strings.Join(parts, ", ")
+}
+
+// sortedSeverities returns the severities in the given counts, ordered from
+// most to least severe. Unrecognized severities are listed last.
+func sortedSeverities(counts map[string]int) []string {
+	severities := lo.Keys(counts)
+	sort.Slice(severities, func(i, j int) bool {
+		ri, rj := severityRank(severities[i]), severityRank(severities[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return severities[i] < severities[j]
+	})
+
+	return severities
+}
+
+func severityRank(severity string) int {
+	if i := lo.IndexOf(severityOrder, severity); i >= 0 {
+		return i
+	}
+
+	return len(severityOrder)
+}
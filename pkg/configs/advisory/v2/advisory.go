@@ -95,6 +95,21 @@ func (adv Advisory) ResolvedAtVersion(version string) bool {
 	}
 }
 
+// SeverityOverride returns the severity rating recorded by the most recent true
+// positive determination that has one, which overrides the severity reported by
+// upstream vulnerability data sources. The second return value is false if no
+// true positive determination has a severity rating.
+func (adv Advisory) SeverityOverride() (vuln.Severity, bool) {
+	sorted := adv.SortedEvents()
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if data, ok := sorted[i].Data.(TruePositiveDetermination); ok && data.Severity != "" {
+			return data.Severity, true
+		}
+	}
+
+	return "", false
+}
+
 // Validate returns an error if the advisory is invalid.
 func (adv Advisory) Validate() error {
 	return labelError(adv.ID,
@@ -3,6 +3,8 @@ package v2
 import (
 	"testing"
 	"time"
+
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
 )
 
 func TestAdvisory_Validate(t *testing.T) {
@@ -102,3 +104,29 @@ func TestAdvisory_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestAdvisory_SeverityOverride(t *testing.T) {
+	t1 := Timestamp(time.Date(2023, 9, 26, 0, 0, 0, 0, time.UTC))
+	t2 := Timestamp(time.Date(2023, 10, 26, 0, 0, 0, 0, time.UTC))
+	t3 := Timestamp(time.Date(2023, 11, 26, 0, 0, 0, 0, time.UTC))
+
+	adv := Advisory{
+		ID: "CVE-2023-0001",
+		Events: []Event{
+			{Timestamp: t1, Type: EventTypeDetection, Data: Detection{Type: DetectionTypeManual}},
+		},
+	}
+	if _, ok := adv.SeverityOverride(); ok {
+		t.Errorf("SeverityOverride() ok = true for an advisory without a severity rating")
+	}
+
+	// The events are deliberately out of order.
+	adv.Events = append(adv.Events,
+		Event{Timestamp: t3, Type: EventTypeTruePositiveDetermination, Data: TruePositiveDetermination{Note: "still investigating"}},
+		Event{Timestamp: t2, Type: EventTypeTruePositiveDetermination, Data: TruePositiveDetermination{Severity: vuln.SeverityLow}},
+	)
+	got, ok := adv.SeverityOverride()
+	if !ok || got != vuln.SeverityLow {
+		t.Errorf("SeverityOverride() = %q, %v, want %q, true", got, ok, vuln.SeverityLow)
+	}
+}
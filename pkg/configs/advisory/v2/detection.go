@@ -13,6 +13,7 @@ import (
 const (
 	DetectionTypeManual = "manual"
 	DetectionTypeNVDAPI = "nvdapi"
+	DetectionTypeScanV1 = "scan/v1"
 )
 
 var (
@@ -20,6 +21,7 @@ var (
 	DetectionTypes = []string{
 		DetectionTypeManual,
 		DetectionTypeNVDAPI,
+		DetectionTypeScanV1,
 	}
 )
 
@@ -58,6 +60,9 @@ func (d Detection) validateData() error {
 
 	case DetectionTypeNVDAPI:
 		return validateTypedDetectionData[DetectionNVDAPI](d.Data)
+
+	case DetectionTypeScanV1:
+		return validateTypedDetectionData[DetectionScanV1](d.Data)
 	}
 
 	return nil
@@ -98,6 +103,13 @@ func (d *Detection) UnmarshalYAML(v *yaml.Node) error {
 		}
 		d.Data = data
 
+	case DetectionTypeScanV1:
+		var data DetectionScanV1
+		if err := partial.Data.Decode(&data); err != nil {
+			return err
+		}
+		d.Data = data
+
 	default:
 		return fmt.Errorf("invalid detection type %q, must be one of [%s]", partial.Type, strings.Join(DetectionTypes, ", "))
 	}
@@ -123,3 +135,53 @@ func (d DetectionNVDAPI) Validate() error {
 		),
 	)
 }
+
+// DetectionScanV1 is the data associated with DetectionTypeScanV1. It
+// describes the component, found by scanning a built package, that matched
+// the vulnerability.
+type DetectionScanV1 struct {
+	// SubpackageName is the name of the scanned APK, which can be a subpackage
+	// of the package the advisory is for. It's omitted if it's the same as the
+	// package name.
+	SubpackageName string `yaml:"subpackageName,omitempty"`
+
+	// ComponentID is the scanner's ID for the matched component.
+	ComponentID string `yaml:"componentID,omitempty"`
+
+	// ComponentName is the name of the matched component.
+	ComponentName string `yaml:"componentName"`
+
+	// ComponentVersion is the version of the matched component.
+	ComponentVersion string `yaml:"componentVersion"`
+
+	// ComponentType is the type of the matched component, e.g. "go-module".
+	ComponentType string `yaml:"componentType"`
+
+	// ComponentLocation is where the matched component was found in the scanned
+	// APK, e.g. "/usr/bin/crane".
+	ComponentLocation string `yaml:"componentLocation"`
+
+	// Scanner is the name of the scanner that found the match, e.g. "grype".
+	Scanner string `yaml:"scanner"`
+}
+
+// Validate returns an error if the DetectionScanV1 data is invalid.
+func (d DetectionScanV1) Validate() error {
+	return labelError("scan/v1 detection data",
+		errors.Join(
+			labelError("componentName", validateNotEmpty(d.ComponentName)),
+			labelError("componentVersion", validateNotEmpty(d.ComponentVersion)),
+			labelError("componentType", validateNotEmpty(d.ComponentType)),
+			labelError("componentLocation", validateNotEmpty(d.ComponentLocation)),
+			labelError("scanner", validateNotEmpty(d.Scanner)),
+		),
+	)
+}
+
+func validateNotEmpty(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("must not be empty")
+	}
+
+	return nil
+}
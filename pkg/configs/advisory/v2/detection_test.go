@@ -37,6 +37,31 @@ func TestDetection_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "scan/v1",
+			detection: Detection{
+				Type: DetectionTypeScanV1,
+				Data: DetectionScanV1{
+					ComponentName:     "github.com/example/module",
+					ComponentVersion:  "v1.2.3",
+					ComponentType:     "go-module",
+					ComponentLocation: "/usr/bin/example",
+					Scanner:           "grype",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "scan/v1 missing component data",
+			detection: Detection{
+				Type: DetectionTypeScanV1,
+				Data: DetectionScanV1{
+					ComponentName: "github.com/example/module",
+					Scanner:       "grype",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid type",
 			detection: Detection{
@@ -146,6 +146,22 @@ func TestDocument_full_coverage(t *testing.T) {
 							},
 						},
 					},
+					{
+						Timestamp: testTime,
+						Type:      EventTypeDetection,
+						Data: Detection{
+							Type: DetectionTypeScanV1,
+							Data: DetectionScanV1{
+								SubpackageName:    "full-compat",
+								ComponentID:       "5167656fc1b9f5e4",
+								ComponentName:     "github.com/example/module",
+								ComponentVersion:  "v1.2.3",
+								ComponentType:     "go-module",
+								ComponentLocation: "/usr/bin/full",
+								Scanner:           "grype",
+							},
+						},
+					},
 					{
 						Timestamp: testTime,
 						Type:      EventTypeTruePositiveDetermination,
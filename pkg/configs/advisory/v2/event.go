@@ -146,7 +146,11 @@ func (e Event) validateData() error {
 		return validateTypedEventData[Detection](e.Data)
 
 	case EventTypeTruePositiveDetermination:
-		// no validation needed currently
+		if e.Data == nil {
+			// data is optional for this event type
+			return nil
+		}
+		return validateTypedEventData[TruePositiveDetermination](e.Data)
 
 	case EventTypeFixed:
 		return validateTypedEventData[Fixed](e.Data)
@@ -1,7 +1,32 @@
 package v2
 
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
 // TruePositiveDetermination is an event that indicates that a previously
 // detected vulnerability was acknowledged to be a true positive.
 type TruePositiveDetermination struct {
 	Note string `yaml:"note,omitempty"`
+
+	// Severity is the distro maintainers' own rating of the vulnerability's
+	// severity as it applies to the distro package. If set, it overrides the
+	// severity reported by upstream vulnerability data sources.
+	Severity vuln.Severity `yaml:"severity,omitempty"`
+}
+
+// Validate returns an error if the TruePositiveDetermination data is invalid.
+func (tp TruePositiveDetermination) Validate() error {
+	if tp.Severity == "" {
+		return nil
+	}
+
+	if !slices.Contains(vuln.Severities, tp.Severity) {
+		return fmt.Errorf("invalid severity %q, must be one of [%s]", tp.Severity, vuln.SeveritiesString())
+	}
+
+	return nil
 }
@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+func TestTruePositiveDetermination_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tp      TruePositiveDetermination
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			tp:      TruePositiveDetermination{},
+			wantErr: false,
+		},
+		{
+			name: "with severity",
+			tp: TruePositiveDetermination{
+				Note:     "only exploitable with a non-default configuration",
+				Severity: vuln.SeverityLow,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid severity",
+			tp: TruePositiveDetermination{
+				Severity: "Severe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tp.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -551,12 +551,11 @@ func (g Graph) ReverseSorted() ([]Package, error) {
 // In other words, the new subgraph will contain all dependencies (transitively)
 // of all packages whose names were given as the `roots` argument.
 func (g Graph) SubgraphWithRoots(roots []string) (*Graph, error) {
-	// subgraph needs to create a new graph, but it also has a subset of Packages
-	subPkgs, err := g.packages.Sub(roots...)
+	adjacencyMap, err := g.Graph.AdjacencyMap()
 	if err != nil {
 		return nil, err
 	}
-	return NewGraph(subPkgs)
+	return g.subgraphReachableFrom(roots, adjacencyMap)
 }
 
 // SubgraphWithLeaves returns a new Graph that's a subgraph of g, where the set of
@@ -566,55 +565,60 @@ func (g Graph) SubgraphWithRoots(roots []string) (*Graph, error) {
 // In other words, the new subgraph will contain all packages (transitively) that
 // are dependent on the packages whose names were given as the `leaves` argument.
 func (g Graph) SubgraphWithLeaves(leaves []string) (*Graph, error) {
-	subgraph := &Graph{
-		Graph:  newGraph(),
-		opts:   g.opts,
-		byName: map[string][]string{},
-	}
-	var names []string
-
 	predecessorMap, err := g.Graph.PredecessorMap()
 	if err != nil {
 		return nil, err
 	}
+	return g.subgraphReachableFrom(leaves, predecessorMap)
+}
+
+// subgraphReachableFrom returns a new Graph that's a subgraph of g, containing
+// the nodes for the given package names and every node reachable from them by
+// following the edges in m, which is either g's adjacency map or its
+// predecessor map.
+func (g Graph) subgraphReachableFrom(names []string, m map[string]map[string]graph.Edge[string]) (*Graph, error) {
+	reachable := map[string]bool{}
 
-	var walk func(key string) error // Go can be so awkward sometimes!
-	walk = func(key string) error {
-		c := g.packages.ConfigByKey(key)
-		if c == nil {
-			return fmt.Errorf("unable to find package %q", key)
+	var walk func(key string)
+	walk = func(key string) {
+		if reachable[key] {
+			return
 		}
-		if err := subgraph.addVertex(c); err != nil && !errors.Is(err, graph.ErrVertexAlreadyExists) {
-			return err
+		reachable[key] = true
+		for next := range m[key] {
+			walk(next)
 		}
-		names = append(names, key)
-
-		for dependent := range predecessorMap[key] {
-			c := g.packages.ConfigByKey(dependent)
-			if c == nil {
-				return fmt.Errorf("unable to find package %q", dependent)
-			}
-			if err := subgraph.addVertex(c); err != nil && !errors.Is(err, graph.ErrVertexAlreadyExists) {
-				return err
-			}
-			if err := subgraph.Graph.AddEdge(dependent, key); err != nil && !errors.Is(err, graph.ErrEdgeAlreadyExists) {
-				return err
-			}
+	}
 
-			if err := walk(dependent); err != nil {
-				return err
-			}
+	for _, name := range names {
+		keys, ok := g.byName[name]
+		if !ok {
+			return nil, fmt.Errorf("package %q not found", name)
+		}
+		for _, key := range keys {
+			walk(key)
 		}
-		return nil
 	}
 
-	for _, leaf := range leaves {
-		if err := walk(leaf); err != nil {
+	subgraph, err := g.Filter(func(p Package) bool {
+		return reachable[PackageHash(p)]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the subgraph's packages are the local packages it contains
+	var local []string
+	for key := range reachable {
+		p, err := g.Graph.Vertex(key)
+		if err != nil {
 			return nil, err
 		}
+		if p.Source() == Local {
+			local = append(local, p.Name())
+		}
 	}
-
-	subPkgs, err := g.packages.Sub(names...)
+	subPkgs, err := g.packages.Sub(local...)
 	if err != nil {
 		return nil, err
 	}
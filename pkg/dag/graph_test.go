@@ -3,6 +3,7 @@ package dag
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -256,3 +257,60 @@ func TestNewGraph(t *testing.T) {
 		}
 	})
 }
+
+func TestSubgraph(t *testing.T) {
+	var testDir = "testdata/complex"
+	pkgs, err := NewPackages(os.DirFS(testDir), testDir, "")
+	require.NoError(t, err)
+	graph, err := NewGraph(pkgs, WithAllowUnresolved())
+	require.NoError(t, err)
+
+	t.Run("with roots", func(t *testing.T) {
+		sub, err := graph.SubgraphWithRoots([]string{"two"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, sub.Packages())
+
+		nodes, err := sub.Nodes()
+		require.NoError(t, err)
+		assert.Contains(t, nodes, "one:1.2.3-r1@local")
+		assert.NotContains(t, nodes, "one:1.2.8-r1@local")
+		assert.NotContains(t, nodes, "three-other:7.8.9-r1@local")
+		assert.Equal(t, []string{"one:1.2.3-r1@local"}, filterLocal(sub.DependenciesOf("two:4.5.6-r1@local")))
+
+		sub, err = graph.SubgraphWithRoots([]string{"three-other"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "three-other", "two"}, sub.Packages())
+	})
+
+	t.Run("with leaves", func(t *testing.T) {
+		sub, err := graph.SubgraphWithLeaves([]string{"two"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"three-other", "two"}, sub.Packages())
+
+		nodes, err := sub.Nodes()
+		require.NoError(t, err)
+		assert.NotContains(t, nodes, "one:1.2.3-r1@local")
+		assert.Equal(t, []string{"three-other:7.8.9-r1@local"}, sub.RequirementsOf("two:4.5.6-r1@local"))
+
+		sub, err = graph.SubgraphWithLeaves([]string{"one"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "three-other", "two"}, sub.Packages())
+	})
+
+	t.Run("unknown package", func(t *testing.T) {
+		_, err := graph.SubgraphWithRoots([]string{"four"})
+		assert.Error(t, err)
+		_, err = graph.SubgraphWithLeaves([]string{"four"})
+		assert.Error(t, err)
+	})
+}
+
+func filterLocal(nodes []string) []string {
+	var local []string
+	for _, n := range nodes {
+		if strings.HasSuffix(n, "@"+Local) {
+			local = append(local, n)
+		}
+	}
+	return local
+}
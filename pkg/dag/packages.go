@@ -309,6 +309,7 @@ func (p Packages) PackageNames() []string {
 // Sub returns a new Packages whose members are the named packages or provides that are listed.
 // If a listed element is a provides, automatically includes the origin package that provides it.
 // If a listed element is a subpackage, automatically includes the origin package that contains it.
+// Each included origin package comes with all of its subpackages and provides.
 // If a listed element does not exist, returns an error.
 func (p Packages) Sub(names ...string) (*Packages, error) {
 	pkgs := &Packages{
@@ -316,16 +317,34 @@ func (p Packages) Sub(names ...string) (*Packages, error) {
 		index:    make(map[string]*Configuration),
 		packages: make(map[string][]*Configuration),
 	}
+
+	// subpackages and provides share the parsed configuration of their origin package
+	origins := map[*config.Configuration]bool{}
 	for _, name := range names {
-		if c, ok := p.configs[name]; ok {
-			for _, config := range c {
-				if err := pkgs.addConfiguration(name, config); err != nil {
-					return nil, err
-				}
+		c, ok := p.configs[name]
+		if !ok {
+			return nil, fmt.Errorf("package %q not found", name)
+		}
+		for _, config := range c {
+			origins[config.Configuration] = true
+		}
+	}
+
+	for name, c := range p.configs {
+		for _, config := range c {
+			if !origins[config.Configuration] {
+				continue
+			}
+			if err := pkgs.addConfiguration(name, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for name, c := range p.packages {
+		for _, config := range c {
+			if origins[config.Configuration] {
 				pkgs.addPackage(name, config)
 			}
-		} else {
-			return nil, fmt.Errorf("package %q not found", name)
 		}
 	}
 	return pkgs, nil
@@ -1,6 +1,7 @@
 package distro
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -53,7 +54,32 @@ func Detect() (Distro, error) {
 	return Distro{}, fmt.Errorf("unable to detect distro")
 }
 
-var errNotDistroRepo = fmt.Errorf("current directory is not a distro or advisories repository")
+// ErrNotDistroRepo is returned when a directory isn't a clone of a known
+// distro's distro or advisories repo.
+var ErrNotDistroRepo = errors.New("directory is not a distro or advisories repository")
+
+// DetectRepo returns the distro whose distro or advisories repo is cloned in the
+// given directory, as identified by the repo's git remotes. Unlike Detect, it
+// doesn't look for the distro's other repo, so the returned Distro has only one
+// of DistroRepoDir and AdvisoriesRepoDir set. If the directory isn't a clone of
+// a known distro's repo, DetectRepo returns an error that wraps
+// ErrNotDistroRepo.
+func DetectRepo(dir string) (Distro, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Distro{}, err
+	}
+
+	d, err := detectDistroInRepo(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return Distro{}, fmt.Errorf("%s: %w", dir, ErrNotDistroRepo)
+	}
+	if errors.Is(err, ErrNotDistroRepo) {
+		return Distro{}, fmt.Errorf("%s: %w", dir, err)
+	}
+
+	return d, err
+}
 
 func detectDistroInRepo(dir string) (Distro, error) {
 	repo, err := git.PlainOpen(dir)
@@ -76,26 +102,20 @@ func detectDistroInRepo(dir string) (Distro, error) {
 
 		for _, d := range []knownDistro{wolfiDistro, chainguardDistro} {
 			if slices.Contains(d.distroRemoteURLs, url) {
-				return Distro{
-					Name:                   d.name,
-					DistroRepoDir:          dir,
-					APKRepositoryURL:       d.apkRepositoryURL,
-					SupportedArchitectures: d.supportedArchitectures,
-				}, nil
+				distro := d.distro()
+				distro.DistroRepoDir = dir
+				return distro, nil
 			}
 
 			if slices.Contains(d.advisoriesRemoteURLs, url) {
-				return Distro{
-					Name:                   d.name,
-					AdvisoriesRepoDir:      dir,
-					APKRepositoryURL:       d.apkRepositoryURL,
-					SupportedArchitectures: d.supportedArchitectures,
-				}, nil
+				distro := d.distro()
+				distro.AdvisoriesRepoDir = dir
+				return distro, nil
 			}
 		}
 	}
 
-	return Distro{}, errNotDistroRepo
+	return Distro{}, ErrNotDistroRepo
 }
 
 func getDistroByName(name string) (knownDistro, error) {
@@ -96,3 +96,39 @@ func TestDetect(t *testing.T) {
 	assert.Equal(t, expectedAdvisoriesRepoDir, d.AdvisoriesRepoDir)
 	assert.Equal(t, "https://packages.wolfi.dev/os", d.APKRepositoryURL)
 }
+
+func TestDetectRepo(t *testing.T) {
+	// A distro repo on its own, without the advisories repo alongside it, as in
+	// a CI checkout.
+	tempDir := t.TempDir()
+
+	distroDir := filepath.Join(tempDir, "enterprise-packages")
+	repo, err := git.PlainInit(distroDir, false)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{"https://github.com/chainguard-dev/enterprise-packages.git"},
+	})
+	require.NoError(t, err)
+
+	d, err := DetectRepo(distroDir)
+	require.NoError(t, err)
+	assert.Equal(t, "Chainguard", d.Name)
+	assert.Equal(t, distroDir, d.DistroRepoDir)
+	assert.Equal(t, "https://packages.cgr.dev/os", d.APKRepositoryURL)
+
+	otherDir := filepath.Join(tempDir, "other")
+	repo, err = git.PlainInit(otherDir, false)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{"https://some-other-repo.git"},
+	})
+	require.NoError(t, err)
+
+	_, err = DetectRepo(otherDir)
+	assert.ErrorIs(t, err, ErrNotDistroRepo)
+
+	_, err = DetectRepo(t.TempDir())
+	assert.ErrorIs(t, err, ErrNotDistroRepo)
+}
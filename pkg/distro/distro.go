@@ -18,17 +18,49 @@ type Distro struct {
 	// "https://packages.wolfi.dev/os").
 	APKRepositoryURL string
 
+	// APKRepositoryKeyURL is the URL to the public key that the distro's
+	// package repository is signed with, if it's known (e.g.
+	// "https://packages.wolfi.dev/os/wolfi-signing.rsa.pub").
+	APKRepositoryKeyURL string
+
+	// SourceCacheURL is the location of the distro's cache of package sources,
+	// if it has one (e.g. "gs://wolfi-sources/").
+	SourceCacheURL string
+
 	// SupportedArchitectures is a list of architectures supported by the distro.
 	SupportedArchitectures []string
 }
 
+// ByName returns the known distro with the given name (e.g. "Wolfi"). The
+// returned Distro doesn't have any repo directories set.
+func ByName(name string) (Distro, error) {
+	d, err := getDistroByName(name)
+	if err != nil {
+		return Distro{}, err
+	}
+
+	return d.distro(), nil
+}
+
 type knownDistro struct {
 	name                                   string
 	distroRemoteURLs, advisoriesRemoteURLs []string
 	apkRepositoryURL                       string
+	apkRepositoryKeyURL                    string
+	sourceCacheURL                         string
 	supportedArchitectures                 []string
 }
 
+func (d knownDistro) distro() Distro {
+	return Distro{
+		Name:                   d.name,
+		APKRepositoryURL:       d.apkRepositoryURL,
+		APKRepositoryKeyURL:    d.apkRepositoryKeyURL,
+		SourceCacheURL:         d.sourceCacheURL,
+		SupportedArchitectures: d.supportedArchitectures,
+	}
+}
+
 var (
 	wolfiDistro = knownDistro{
 		name: "Wolfi",
@@ -44,7 +76,9 @@ var (
 			"https://github.com/wolfi-dev/advisories.git",
 			"https://github.com/wolfi-dev/advisories",
 		},
-		apkRepositoryURL: "https://packages.wolfi.dev/os",
+		apkRepositoryURL:    "https://packages.wolfi.dev/os",
+		apkRepositoryKeyURL: "https://packages.wolfi.dev/os/wolfi-signing.rsa.pub",
+		sourceCacheURL:      "gs://wolfi-sources/",
 		supportedArchitectures: []string{
 			"x86_64",
 			"aarch64",
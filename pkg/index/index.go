@@ -5,11 +5,15 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
+// Index returns the APKINDEX for the given arch of the given repository, which
+// is either a URL, a local repository directory, or the path of a local APKINDEX
+// file.
 func Index(arch, repo string) (*repository.ApkIndex, error) {
 	var rc io.ReadCloser
 	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") {
@@ -28,6 +32,11 @@ func Index(arch, repo string) (*repository.ApkIndex, error) {
 		}
 		rc = resp.Body
 	} else {
+		// A local repository directory has an index for each arch, but a
+		// local path can also be the index itself.
+		if fi, err := os.Stat(repo); err == nil && fi.IsDir() {
+			repo = filepath.Join(repo, arch, "APKINDEX.tar.gz")
+		}
 		f, err := os.Open(repo)
 		if err != nil {
 			return nil, fmt.Errorf("opening %q: %w", repo, err)
@@ -2,10 +2,17 @@ package sbom
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/anchore/syft/syft/sbom"
@@ -13,7 +20,24 @@ import (
 
 var sbomCacheDirectory = path.Join(xdg.CacheHome, "wolfictl", "sbom", "apk")
 
-func cachedSBOMPath(inputFilePath string, f io.Reader) (string, error) {
+// sbomCacheVersion identifies the configuration used to generate SBOMs, such
+// as the enabled catalogers and the metadata recorded for the APK. It should be
+// incremented whenever Generate's output changes, so that SBOMs cached by
+// older versions of wolfictl aren't used.
+const sbomCacheVersion = 5
+
+// validDistroID matches the distro IDs that can be used as a directory in the
+// cache, so that an ID like "../x" can't escape it.
+var validDistroID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func cachedSBOMPath(inputFilePath string, f io.Reader, distroID string) (string, error) {
+	if distroID == "" {
+		distroID = "unknown"
+	}
+	if !validDistroID.MatchString(distroID) {
+		return "", fmt.Errorf("invalid distro ID %q", distroID)
+	}
+
 	h := sha256.New()
 	_, err := io.Copy(h, f)
 	if err != nil {
@@ -24,7 +48,12 @@ func cachedSBOMPath(inputFilePath string, f io.Reader) (string, error) {
 	apkFilename := path.Base(inputFilePath)
 	apkFilename = apkFilename[:len(apkFilename)-len(path.Ext(apkFilename))]
 
-	return path.Join(sbomCacheDirectory, fmt.Sprintf("%s-sha256-%x.syft.json", apkFilename, digest)), nil
+	return path.Join(
+		sbomCacheDirectory,
+		distroID,
+		fmt.Sprintf("v%d", sbomCacheVersion),
+		fmt.Sprintf("%s-sha256-%x.syft.json", apkFilename, digest),
+	), nil
 }
 
 // CachedGenerate behaves similarly to Generate, but it caches the result of the
@@ -35,7 +64,7 @@ func cachedSBOMPath(inputFilePath string, f io.Reader) (string, error) {
 func CachedGenerate(inputFilePath string, f io.ReadSeeker, distroID string) (*sbom.SBOM, error) {
 	// Check cache first
 
-	cachedPath, err := cachedSBOMPath(inputFilePath, f)
+	cachedPath, err := cachedSBOMPath(inputFilePath, f, distroID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute cached SBOM path: %w", err)
 	}
@@ -89,6 +118,12 @@ func CachedGenerate(inputFilePath string, f io.ReadSeeker, distroID string) (*sb
 	// Cache hit!
 
 	defer cached.Close()
+
+	// Record the use of the cached SBOM, so that pruning the cache evicts the
+	// least recently used SBOMs first.
+	now := time.Now()
+	_ = os.Chtimes(cachedPath, now, now)
+
 	s, err := FromSyftJSON(cached)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode cached SBOM (%s): %w", cachedPath, err)
@@ -96,3 +131,161 @@ func CachedGenerate(inputFilePath string, f io.ReadSeeker, distroID string) (*sb
 
 	return s, nil
 }
+
+// CacheEntry describes an SBOM in the cache.
+type CacheEntry struct {
+	// Path is the path to the cached SBOM, relative to the cache directory.
+	Path string `json:"path"`
+
+	// Size is the size of the cached SBOM, in bytes.
+	Size int64 `json:"size"`
+
+	// LastUsed is the time the cached SBOM was last written or read.
+	LastUsed time.Time `json:"lastUsed"`
+
+	// Stale is true if the SBOM was cached by a version of wolfictl that
+	// generated SBOMs differently, so it will never be used again.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// CacheDirectory returns the directory where generated SBOMs are cached.
+func CacheDirectory() string {
+	return sbomCacheDirectory
+}
+
+// ListCache returns the SBOMs in the cache, sorted by path.
+func ListCache() ([]CacheEntry, error) {
+	var entries []CacheEntry
+	err := filepath.WalkDir(sbomCacheDirectory, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(sbomCacheDirectory, p)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, CacheEntry{
+			Path:     filepath.ToSlash(rel),
+			Size:     info.Size(),
+			LastUsed: info.ModTime(),
+			Stale:    isStaleCachePath(filepath.ToSlash(rel)),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SBOM cache: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, nil
+}
+
+// isStaleCachePath returns true if the given path, relative to the cache
+// directory, doesn't belong to the current cache version.
+func isStaleCachePath(rel string) bool {
+	parts := strings.Split(rel, "/")
+	return len(parts) != 3 || parts[1] != fmt.Sprintf("v%d", sbomCacheVersion)
+}
+
+// PruneOptions configures which SBOMs are evicted from the cache by PruneCache.
+type PruneOptions struct {
+	// MaxAge is the maximum time since a cached SBOM was last used. Older SBOMs
+	// are evicted. Zero means no limit.
+	MaxAge time.Duration
+
+	// MaxSize is the maximum total size of the cache, in bytes. The least
+	// recently used SBOMs are evicted until the cache fits. Zero means no limit.
+	MaxSize int64
+
+	// Now is the time against which MaxAge is measured. If zero, the current
+	// time is used.
+	Now time.Time
+}
+
+// PruneCache evicts stale SBOMs, and then SBOMs that exceed the given age and
+// size limits, from the cache. Directories left empty by the evictions are
+// removed too. It returns the evicted entries.
+func PruneCache(opts PruneOptions) ([]CacheEntry, error) {
+	entries, err := ListCache()
+	if err != nil {
+		return nil, err
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	// Evict the least recently used SBOMs first.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+
+	var evicted []CacheEntry
+	for _, e := range entries {
+		expired := opts.MaxAge > 0 && now.Sub(e.LastUsed) > opts.MaxAge
+		oversize := opts.MaxSize > 0 && total > opts.MaxSize
+		if !e.Stale && !expired && !oversize {
+			continue
+		}
+
+		p := filepath.Join(sbomCacheDirectory, filepath.FromSlash(e.Path))
+		err := os.Remove(p)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return evicted, fmt.Errorf("failed to evict cached SBOM %q: %w", e.Path, err)
+		}
+		removeEmptyParents(p)
+
+		total -= e.Size
+		evicted = append(evicted, e)
+	}
+
+	return evicted, nil
+}
+
+// removeEmptyParents removes the directories that contain p, up to but not
+// including the cache directory, for as long as they're empty.
+func removeEmptyParents(p string) {
+	for dir := filepath.Dir(p); dir != sbomCacheDirectory; dir = filepath.Dir(dir) {
+		rel, err := filepath.Rel(sbomCacheDirectory, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return
+		}
+
+		// Removing a directory that isn't empty fails, which is where we stop.
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
+
+// ClearCache removes all SBOMs from the cache.
+func ClearCache() error {
+	err := os.RemoveAll(sbomCacheDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to clear SBOM cache: %w", err)
+	}
+
+	return nil
+}
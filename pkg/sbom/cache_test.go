@@ -0,0 +1,159 @@
+package sbom
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedSBOMPath(t *testing.T) {
+	wolfi, err := cachedSBOMPath("/tmp/ko-0.13.0-r3.apk", strings.NewReader("apk"), "wolfi")
+	require.NoError(t, err)
+	chainguard, err := cachedSBOMPath("/tmp/ko-0.13.0-r3.apk", strings.NewReader("apk"), "chainguard")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, wolfi, chainguard)
+
+	rel, err := filepath.Rel(sbomCacheDirectory, wolfi)
+	require.NoError(t, err)
+	assert.Regexp(t, `^wolfi/v\d+/ko-0\.13\.0-r3-sha256-[0-9a-f]{64}\.syft\.json$`, filepath.ToSlash(rel))
+	assert.False(t, isStaleCachePath(filepath.ToSlash(rel)))
+
+	unknown, err := cachedSBOMPath("/tmp/ko-0.13.0-r3.apk", strings.NewReader("apk"), "")
+	require.NoError(t, err)
+	assert.Contains(t, filepath.ToSlash(unknown), "/unknown/")
+
+	for _, distroID := range []string{"..", "../x", "wolfi/../..", ".hidden", `wolfi\x`} {
+		_, err := cachedSBOMPath("/tmp/ko-0.13.0-r3.apk", strings.NewReader("apk"), distroID)
+		assert.Error(t, err, distroID)
+	}
+}
+
+func TestPruneCache(t *testing.T) {
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// The directory of SBOMs cached by the current version.
+	current := fmt.Sprintf("wolfi/v%d/", sbomCacheVersion)
+
+	setup := func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		original := sbomCacheDirectory
+		sbomCacheDirectory = dir
+		t.Cleanup(func() { sbomCacheDirectory = original })
+
+		files := []struct {
+			path     string
+			size     int
+			lastUsed time.Time
+		}{
+			{"legacy-sha256-aaaa.syft.json", 10, now.Add(-time.Hour)},
+			{"wolfi/v1/ko-sha256-bbbb.syft.json", 10, now.Add(-time.Hour)},
+			{current + "old-sha256-cccc.syft.json", 100, now.Add(-30 * 24 * time.Hour)},
+			{current + "mid-sha256-dddd.syft.json", 100, now.Add(-2 * time.Hour)},
+			{current + "new-sha256-eeee.syft.json", 100, now.Add(-time.Hour)},
+		}
+		for _, f := range files {
+			p := filepath.Join(dir, filepath.FromSlash(f.path))
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+			require.NoError(t, os.WriteFile(p, make([]byte, f.size), 0o644))
+			require.NoError(t, os.Chtimes(p, f.lastUsed, f.lastUsed))
+		}
+	}
+
+	paths := func(entries []CacheEntry) []string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.Path)
+		}
+		return result
+	}
+
+	t.Run("list", func(t *testing.T) {
+		setup(t)
+
+		entries, err := ListCache()
+		require.NoError(t, err)
+		require.Len(t, entries, 5)
+
+		var stale []string
+		for _, e := range entries {
+			if e.Stale {
+				stale = append(stale, e.Path)
+			}
+		}
+		assert.Equal(t, []string{"legacy-sha256-aaaa.syft.json", "wolfi/v1/ko-sha256-bbbb.syft.json"}, stale)
+	})
+
+	t.Run("stale only", func(t *testing.T) {
+		setup(t)
+
+		evicted, err := PruneCache(PruneOptions{Now: now})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"legacy-sha256-aaaa.syft.json", "wolfi/v1/ko-sha256-bbbb.syft.json"}, paths(evicted))
+
+		remaining, err := ListCache()
+		require.NoError(t, err)
+		assert.Len(t, remaining, 3)
+
+		// The directories of the stale version are removed once they're empty,
+		// but not the cache directory itself.
+		assert.NoDirExists(t, filepath.Join(sbomCacheDirectory, "wolfi", "v1"))
+		assert.DirExists(t, filepath.Join(sbomCacheDirectory, "wolfi"))
+		assert.DirExists(t, sbomCacheDirectory)
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		setup(t)
+
+		evicted, err := PruneCache(PruneOptions{MaxAge: 7 * 24 * time.Hour, Now: now})
+		require.NoError(t, err)
+		assert.Contains(t, paths(evicted), current+"old-sha256-cccc.syft.json")
+		assert.NotContains(t, paths(evicted), current+"mid-sha256-dddd.syft.json")
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		setup(t)
+
+		evicted, err := PruneCache(PruneOptions{MaxSize: 150, Now: now})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"legacy-sha256-aaaa.syft.json",
+			"wolfi/v1/ko-sha256-bbbb.syft.json",
+			current + "old-sha256-cccc.syft.json",
+			current + "mid-sha256-dddd.syft.json",
+		}, paths(evicted))
+
+		remaining, err := ListCache()
+		require.NoError(t, err)
+		assert.Equal(t, []string{current + "new-sha256-eeee.syft.json"}, paths(remaining))
+	})
+
+	t.Run("empty directories", func(t *testing.T) {
+		setup(t)
+
+		_, err := PruneCache(PruneOptions{MaxSize: 1, Now: now})
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(sbomCacheDirectory)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		setup(t)
+
+		require.NoError(t, ClearCache())
+
+		remaining, err := ListCache()
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
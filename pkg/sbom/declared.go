@@ -0,0 +1,62 @@
+package sbom
+
+import (
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"golang.org/x/exp/slices"
+)
+
+// declaredDependencyRelationships returns a DEPENDENCY_OF relationship to the
+// APK from each cataloged package that the APK declares as a dependency. The
+// dependencies that aren't cataloged (usually other APKs) are only recorded in
+// the APK's metadata, rather than as packages of their own, since they aren't
+// part of what the APK installs.
+func declaredDependencyRelationships(apk pkg.Package, packages *pkg.Collection) []artifact.Relationship {
+	metadata, ok := apk.Metadata.(pkg.ApkMetadata)
+	if !ok {
+		return nil
+	}
+
+	var relationships []artifact.Relationship
+	for _, name := range dependencyNames(metadata.Dependencies) {
+		for _, p := range packages.PackagesByName(name) {
+			if p.ID() == apk.ID() {
+				continue
+			}
+
+			relationships = append(relationships, artifact.Relationship{
+				From: p,
+				To:   apk,
+				Type: artifact.DependencyOfRelationship,
+			})
+		}
+	}
+
+	return relationships
+}
+
+// dependencyNames returns the sorted names of the given APK dependencies,
+// without any version constraints. Conflicts (e.g. "!foo") aren't
+// dependencies, so they're skipped.
+func dependencyNames(dependencies []string) []string {
+	var names []string
+	for _, dep := range dependencies {
+		if dep == "" || strings.HasPrefix(dep, "!") {
+			continue
+		}
+
+		name := dep
+		if i := strings.IndexAny(dep, "<>=~"); i > 0 {
+			name = dep[:i]
+		}
+
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,42 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclaredDependencyRelationships(t *testing.T) {
+	apk := pkg.Package{
+		Name:    "py3-example",
+		Version: "1.0.0-r0",
+		Type:    pkg.ApkPkg,
+		Metadata: pkg.ApkMetadata{
+			Dependencies: []string{"so:libc.so.6", "py3-vendored>=2", "!py3-example-legacy"},
+			Provides:     []string{"py3.11-example=1.0.0-r0"},
+		},
+	}
+	apk.SetID()
+
+	vendored := pkg.Package{Name: "py3-vendored", Version: "2.1.0", Type: pkg.PythonPkg}
+	vendored.SetID()
+	other := pkg.Package{Name: "requests", Version: "2.31.0", Type: pkg.PythonPkg}
+	other.SetID()
+
+	relationships := declaredDependencyRelationships(apk, pkg.NewCollection(apk, vendored, other))
+
+	// Only the dependency that was cataloged is related to the APK. The others
+	// stay in the APK's metadata, and nothing is added for what it provides.
+	assert.Equal(t, []artifact.Relationship{
+		{From: vendored, To: apk, Type: artifact.DependencyOfRelationship},
+	}, relationships)
+}
+
+func TestDependencyNames(t *testing.T) {
+	assert.Equal(t,
+		[]string{"ca-certificates-bundle", "so:libc.so.6"},
+		dependencyNames([]string{"so:libc.so.6", "ca-certificates-bundle>=20230506", "so:libc.so.6", "!ko-legacy", ""}),
+	)
+}
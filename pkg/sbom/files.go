@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"crypto"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/file/cataloger/filedigest"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// catalogFileDigests returns the SHA-256 digest of each regular file the APK
+// installs. APK control files, like .PKGINFO and the package signature, aren't
+// installed, so they're skipped.
+func catalogFileDigests(resolver file.Resolver) (map[file.Coordinates][]file.Digest, error) {
+	digests, err := filedigest.NewCataloger([]crypto.Hash{crypto.SHA256}).Catalog(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	for coordinates := range digests {
+		if isAPKControlFile(coordinates.RealPath) {
+			delete(digests, coordinates)
+		}
+	}
+
+	return digests, nil
+}
+
+// isAPKControlFile returns true if the given path is one of the dotfiles at the
+// root of an APK, which hold the package's metadata and signatures.
+func isAPKControlFile(p string) bool {
+	p = path.Clean("/" + p)
+	return path.Dir(p) == "/" && strings.HasPrefix(path.Base(p), ".")
+}
+
+// setAPKFiles records the given files, along with their digests, in the APK
+// package's metadata, and updates the package's ID accordingly.
+func setAPKFiles(p *pkg.Package, digests map[file.Coordinates][]file.Digest) {
+	metadata, ok := p.Metadata.(pkg.ApkMetadata)
+	if !ok {
+		return
+	}
+
+	var files []pkg.ApkFileRecord
+	for _, coordinates := range sortedCoordinates(digests) {
+		record := pkg.ApkFileRecord{
+			Path: path.Clean("/" + coordinates.RealPath),
+		}
+		if ds := digests[coordinates]; len(ds) > 0 {
+			d := ds[0]
+			record.Digest = &d
+		}
+		files = append(files, record)
+	}
+
+	metadata.Files = files
+	p.Metadata = metadata
+	p.SetID()
+}
+
+// fileOwnershipRelationships returns a CONTAINS relationship from the APK
+// package to each of the given files.
+func fileOwnershipRelationships(p pkg.Package, digests map[file.Coordinates][]file.Digest) []artifact.Relationship {
+	var relationships []artifact.Relationship
+	for _, coordinates := range sortedCoordinates(digests) {
+		relationships = append(relationships, artifact.Relationship{
+			From: p,
+			To:   coordinates,
+			Type: artifact.ContainsRelationship,
+		})
+	}
+
+	return relationships
+}
+
+func sortedCoordinates(digests map[file.Coordinates][]file.Digest) []file.Coordinates {
+	coordinates := make([]file.Coordinates, 0, len(digests))
+	for c := range digests {
+		coordinates = append(coordinates, c)
+	}
+
+	sort.Slice(coordinates, func(i, j int) bool {
+		return coordinates[i].RealPath < coordinates[j].RealPath
+	})
+
+	return coordinates
+}
@@ -0,0 +1,26 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAPKControlFile(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected bool
+	}{
+		{".PKGINFO", true},
+		{"/.PKGINFO", true},
+		{".SIGN.RSA.wolfi-signing.rsa.pub", true},
+		{"usr/bin/hello", false},
+		{"/etc/.hidden", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isAPKControlFile(tt.path))
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/formats/common/cyclonedxhelpers"
+	"github.com/anchore/syft/syft/formats/common/spdxhelpers"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/google/uuid"
+	"github.com/spdx/tools-golang/spdx"
+	"golang.org/x/exp/slices"
+)
+
+// apkPackageFoundBy identifies the package that describes the APK itself, as
+// opposed to the packages cataloged from the APK's contents.
+const apkPackageFoundBy = "wolfictl"
+
+// spdxCategoryOther is the SPDX external reference category for references
+// that aren't security or package manager references.
+const spdxCategoryOther = "OTHER"
+
+// ToSPDXJSON returns the SBOM as a reader of the SPDX 2.3 JSON format. The APK
+// package is the document's described root, and every other package is related
+// to it through a CONTAINS relationship. The APK's declared dependencies and
+// provides are recorded as external references of the APK package.
+//
+// The output is deterministic: the document namespace is derived from the APK
+// package, and the creation time is taken from SOURCE_DATE_EPOCH (or the Unix
+// epoch, if it's not set).
+func ToSPDXJSON(s *sbom.SBOM) (io.Reader, error) {
+	apk, err := apkPackage(s)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := creationTime()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := spdxhelpers.ToFormatModel(withContainsRelationships(*s, apk))
+
+	apkID, err := spdxPackageID(doc, apk)
+	if err != nil {
+		return nil, err
+	}
+
+	// Syft describes the document with a synthetic package for the scanned
+	// directory, which is an implementation detail of how we catalog the APK.
+	// Replace it with the APK package.
+	var packages []*spdx.Package
+	var rootIDs []spdx.ElementID
+	for _, p := range doc.Packages {
+		if strings.HasPrefix(string(p.PackageSPDXIdentifier), "DocumentRoot-") {
+			rootIDs = append(rootIDs, p.PackageSPDXIdentifier)
+			continue
+		}
+		packages = append(packages, p)
+	}
+
+	var relationships []*spdx.Relationship
+	for _, r := range doc.Relationships {
+		if slices.Contains(rootIDs, r.RefA.ElementRefID) || slices.Contains(rootIDs, r.RefB.ElementRefID) {
+			continue
+		}
+		relationships = append(relationships, r)
+	}
+
+	for _, p := range packages {
+		if p.PackageSPDXIdentifier == apkID {
+			describeAPKPackage(p, apk)
+		}
+	}
+
+	doc.Packages = packages
+	doc.Relationships = append(relationships, &spdx.Relationship{
+		RefA:         spdx.DocElementID{ElementRefID: "DOCUMENT"},
+		Relationship: spdx.RelationshipDescribes,
+		RefB:         spdx.DocElementID{ElementRefID: apkID},
+	})
+
+	doc.DocumentName = fmt.Sprintf("%s-%s", apk.Name, apk.Version)
+	doc.DocumentNamespace = documentNamespace(s, apk)
+	doc.CreationInfo.Created = created.Format(time.RFC3339)
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+
+	return buf, nil
+}
+
+// ToCycloneDXJSON returns the SBOM as a reader of the CycloneDX 1.5 JSON
+// format. The APK package is the BOM's metadata component, and every other
+// package is listed as a component of the BOM.
+//
+// The output is deterministic: the serial number is derived from the APK
+// package, and the timestamp is taken from SOURCE_DATE_EPOCH (or the Unix
+// epoch, if it's not set).
+func ToCycloneDXJSON(s *sbom.SBOM) (io.Reader, error) {
+	apk, err := apkPackage(s)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := creationTime()
+	if err != nil {
+		return nil, err
+	}
+
+	bom := cyclonedxhelpers.ToFormatModel(withContainsRelationships(*s, apk))
+
+	var root *cyclonedx.Component
+	var components []cyclonedx.Component
+	for i := range *bom.Components {
+		c := (*bom.Components)[i]
+		if root == nil && c.PackageURL == apk.PURL && c.Name == apk.Name && c.Version == apk.Version {
+			root = &c
+			continue
+		}
+		components = append(components, c)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("unable to find APK package %q in CycloneDX components", apk.Name)
+	}
+	root.Type = cyclonedx.ComponentTypeApplication
+
+	bom.Components = &components
+
+	bom.SerialNumber = uuid.NewSHA1(uuid.NameSpaceURL, []byte(documentNamespace(s, apk))).URN()
+	bom.Metadata.Timestamp = created.Format(time.RFC3339)
+	bom.Metadata.Component = root
+
+	buf := new(bytes.Buffer)
+	enc := cyclonedx.NewBOMEncoder(buf, cyclonedx.BOMFileFormatJSON)
+	enc.SetPretty(true)
+	enc.SetEscapeHTML(false)
+	err = enc.EncodeVersion(bom, cyclonedx.SpecVersion1_5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+
+	return buf, nil
+}
+
+// apkPackage returns the package that describes the APK itself.
+func apkPackage(s *sbom.SBOM) (pkg.Package, error) {
+	for _, p := range s.Artifacts.Packages.Sorted() {
+		if p.Type == pkg.ApkPkg && p.FoundBy == apkPackageFoundBy {
+			return p, nil
+		}
+	}
+
+	return pkg.Package{}, fmt.Errorf("SBOM doesn't include an APK package")
+}
+
+// describeAPKPackage adds the APK's source information and homepage to its
+// SPDX package, and its declared dependencies and provides as external
+// references. (The Syft JSON and CycloneDX formats carry these in the APK's
+// metadata.)
+func describeAPKPackage(p *spdx.Package, apk pkg.Package) {
+	metadata, ok := apk.Metadata.(pkg.ApkMetadata)
+	if !ok {
+		return
+	}
+
+	p.PackageHomePage = metadata.URL
+	if metadata.GitCommit != "" {
+		p.PackageSourceInfo = fmt.Sprintf("built from commit %s", metadata.GitCommit)
+	}
+
+	for _, dep := range metadata.Dependencies {
+		// Conflicts (e.g. "!foo") aren't dependencies.
+		if dep == "" || strings.HasPrefix(dep, "!") {
+			continue
+		}
+		p.PackageExternalReferences = append(p.PackageExternalReferences, &spdx.PackageExternalReference{
+			Category: spdxCategoryOther,
+			RefType:  "apk-depends",
+			Locator:  dep,
+		})
+	}
+	for _, provided := range metadata.Provides {
+		if provided == "" {
+			continue
+		}
+		p.PackageExternalReferences = append(p.PackageExternalReferences, &spdx.PackageExternalReference{
+			Category: spdxCategoryOther,
+			RefType:  "apk-provides",
+			Locator:  provided,
+		})
+	}
+}
+
+// withContainsRelationships returns a copy of the SBOM with a CONTAINS
+// relationship from the APK package to each other package.
+func withContainsRelationships(s sbom.SBOM, apk pkg.Package) sbom.SBOM {
+	relationships := make([]artifact.Relationship, len(s.Relationships))
+	copy(relationships, s.Relationships)
+
+	for _, p := range s.Artifacts.Packages.Sorted() {
+		if p.ID() == apk.ID() {
+			continue
+		}
+
+		relationships = append(relationships, artifact.Relationship{
+			From: apk,
+			To:   p,
+			Type: artifact.ContainsRelationship,
+		})
+	}
+
+	s.Relationships = relationships
+	return s
+}
+
+func spdxPackageID(doc *spdx.Document, apk pkg.Package) (spdx.ElementID, error) {
+	for _, p := range doc.Packages {
+		if p.PackageName != apk.Name || p.PackageVersion != apk.Version {
+			continue
+		}
+
+		for _, ref := range p.PackageExternalReferences {
+			if ref.RefType == spdx.PackageManagerPURL && ref.Locator == apk.PURL {
+				return p.PackageSPDXIdentifier, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("unable to find APK package %q in SPDX document", apk.Name)
+}
+
+// documentNamespace returns a URI that identifies the SBOM document for the
+// APK. The APK package's ID is a hash of its metadata (including its data
+// checksum), so distinct builds get distinct namespaces.
+func documentNamespace(s *sbom.SBOM, apk pkg.Package) string {
+	distroID := "unknown"
+	if d := s.Artifacts.LinuxDistribution; d != nil && d.ID != "" {
+		distroID = d.ID
+	}
+
+	return fmt.Sprintf("https://spdx.org/spdxdocs/wolfictl/%s/%s-%s-%s", distroID, apk.Name, apk.Version, apk.ID())
+}
+
+// creationTime returns the time to record as the SBOM's creation time, using
+// SOURCE_DATE_EPOCH if it's set.
+func creationTime() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
@@ -0,0 +1,220 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSBOM(t *testing.T) *sbom.SBOM {
+	t.Helper()
+
+	apk := pkg.Package{
+		Name:         "ko",
+		Version:      "0.13.0-r3",
+		FoundBy:      apkPackageFoundBy,
+		Locations:    file.NewLocationSet(file.NewLocation(pkginfoPath)),
+		Type:         pkg.ApkPkg,
+		PURL:         "pkg:apk/wolfi/ko@0.13.0-r3?arch=x86_64",
+		MetadataType: pkg.ApkMetadataType,
+		Metadata: pkg.ApkMetadata{
+			Package:      "ko",
+			Version:      "0.13.0-r3",
+			Architecture: "x86_64",
+			URL:          "https://github.com/ko-build/ko",
+			GitCommit:    "4a5c4e8fbd4e1a2bd6a7e5b7c9b0e1e4a6c8f0d2",
+			Dependencies: []string{"so:libc.so.6", "ca-certificates-bundle>=20230506", "!ko-legacy"},
+			Provides:     []string{"cmd:ko=0.13.0-r3"},
+		},
+	}
+	apk.SetID()
+
+	module := pkg.Package{
+		Name:      "github.com/example/module",
+		Version:   "v1.2.3",
+		FoundBy:   "go-module-binary-cataloger",
+		Locations: file.NewLocationSet(file.NewLocation("usr/bin/ko")),
+		Type:      pkg.GoModulePkg,
+		PURL:      "pkg:golang/github.com/example/module@v1.2.3",
+	}
+	module.SetID()
+
+	return &sbom.SBOM{
+		Artifacts: sbom.Artifacts{
+			Packages:          pkg.NewCollection(apk, module),
+			LinuxDistribution: &linux.Release{ID: "wolfi"},
+		},
+		Source: source.Description{
+			ID:       "(redacted for determinism)",
+			Name:     "ko-0.13.0-r3.apk",
+			Metadata: source.DirectorySourceMetadata{Path: "ko-0.13.0-r3.apk"},
+		},
+		Descriptor: sbom.Descriptor{Name: "wolfictl"},
+	}
+}
+
+func TestToSPDXJSON(t *testing.T) {
+	s := testSBOM(t)
+
+	r, err := ToSPDXJSON(s)
+	require.NoError(t, err)
+	first, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	r, err = ToSPDXJSON(s)
+	require.NoError(t, err)
+	second, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second), "output should be deterministic")
+
+	var doc struct {
+		SPDXVersion  string `json:"spdxVersion"`
+		CreationInfo struct {
+			Created string `json:"created"`
+		} `json:"creationInfo"`
+		Packages []struct {
+			SPDXID       string `json:"SPDXID"`
+			Name         string `json:"name"`
+			Homepage     string `json:"homepage"`
+			SourceInfo   string `json:"sourceInfo"`
+			ExternalRefs []struct {
+				Category string `json:"referenceCategory"`
+				Type     string `json:"referenceType"`
+				Locator  string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+		Relationships []struct {
+			Element string `json:"spdxElementId"`
+			Type    string `json:"relationshipType"`
+			Related string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	require.NoError(t, json.Unmarshal(first, &doc))
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "1970-01-01T00:00:00Z", doc.CreationInfo.Created)
+	require.Len(t, doc.Packages, 2)
+
+	ids := map[string]string{}
+	for _, p := range doc.Packages {
+		ids[p.Name] = p.SPDXID
+
+		if p.Name == "ko" {
+			assert.Equal(t, "https://github.com/ko-build/ko", p.Homepage)
+			assert.Equal(t, "built from commit 4a5c4e8fbd4e1a2bd6a7e5b7c9b0e1e4a6c8f0d2", p.SourceInfo)
+
+			var declared []string
+			for _, ref := range p.ExternalRefs {
+				if ref.Category == "OTHER" {
+					declared = append(declared, ref.Type+" "+ref.Locator)
+				}
+			}
+			assert.Equal(t, []string{
+				"apk-depends so:libc.so.6",
+				"apk-depends ca-certificates-bundle>=20230506",
+				"apk-provides cmd:ko=0.13.0-r3",
+			}, declared)
+		}
+	}
+
+	type relationship struct{ element, typ, related string }
+	var relationships []relationship
+	for _, r := range doc.Relationships {
+		relationships = append(relationships, relationship{r.Element, r.Type, r.Related})
+	}
+	assert.ElementsMatch(t, []relationship{
+		{ids["ko"], "CONTAINS", ids["github.com/example/module"]},
+		{"SPDXRef-DOCUMENT", "DESCRIBES", ids["ko"]},
+	}, relationships)
+}
+
+func TestToSPDXJSON_sourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1696000000")
+
+	r, err := ToSPDXJSON(testSBOM(t))
+	require.NoError(t, err)
+
+	var doc struct {
+		CreationInfo struct {
+			Created string `json:"created"`
+		} `json:"creationInfo"`
+	}
+	require.NoError(t, json.NewDecoder(r).Decode(&doc))
+	assert.Equal(t, "2023-09-29T15:06:40Z", doc.CreationInfo.Created)
+}
+
+func TestToCycloneDXJSON(t *testing.T) {
+	s := testSBOM(t)
+
+	r, err := ToCycloneDXJSON(s)
+	require.NoError(t, err)
+	first, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	r, err = ToCycloneDXJSON(s)
+	require.NoError(t, err)
+	second, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second), "output should be deterministic")
+
+	var bom struct {
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Metadata     struct {
+			Timestamp string `json:"timestamp"`
+			Component struct {
+				Name       string `json:"name"`
+				PURL       string `json:"purl"`
+				Properties []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"properties"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(first, &bom))
+
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Regexp(t, "^urn:uuid:", bom.SerialNumber)
+	assert.Equal(t, "1970-01-01T00:00:00Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "ko", bom.Metadata.Component.Name)
+	assert.Equal(t, "pkg:apk/wolfi/ko@0.13.0-r3?arch=x86_64", bom.Metadata.Component.PURL)
+
+	var names []string
+	for _, c := range bom.Components {
+		names = append(names, c.Name)
+	}
+	assert.Contains(t, names, "github.com/example/module")
+	assert.NotContains(t, names, "ko")
+
+	// The APK's declared dependencies and provides are in its metadata, not
+	// components of their own.
+	assert.NotContains(t, names, "so:libc.so.6")
+	var properties []string
+	for _, p := range bom.Metadata.Component.Properties {
+		properties = append(properties, p.Name+"="+p.Value)
+	}
+	assert.Contains(t, properties, "syft:metadata:pullDependencies:0=so:libc.so.6")
+	assert.Contains(t, properties, "syft:metadata:provides:0=cmd:ko=0.13.0-r3")
+}
+
+func TestToSPDXJSON_noAPKPackage(t *testing.T) {
+	s := testSBOM(t)
+	s.Artifacts.Packages = pkg.NewCollection()
+
+	_, err := ToSPDXJSON(s)
+	assert.Error(t, err)
+}
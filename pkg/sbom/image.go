@@ -0,0 +1,378 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	wtar "github.com/wolfi-dev/wolfictl/pkg/tar"
+)
+
+const (
+	// ociLayoutFile is the marker file found at the root of every OCI image
+	// layout.
+	ociLayoutFile = "oci-layout"
+
+	// dockerManifestFile is the file found at the root of an image tarball
+	// produced by `docker save` (or by go-containerregistry's tarball package).
+	dockerManifestFile = "manifest.json"
+)
+
+// ImageFormat describes how a container image is stored on disk.
+type ImageFormat string
+
+const (
+	// ImageFormatNone means the input isn't a container image.
+	ImageFormatNone ImageFormat = ""
+
+	// ImageFormatOCILayout is an OCI image layout directory.
+	ImageFormatOCILayout ImageFormat = "oci-layout"
+
+	// ImageFormatOCIArchive is a tarball of an OCI image layout.
+	ImageFormatOCIArchive ImageFormat = "oci-archive"
+
+	// ImageFormatTarball is an image tarball as produced by `docker save`.
+	ImageFormatTarball ImageFormat = "docker-archive"
+)
+
+// DetectImageFormat determines whether the file or directory at the given path
+// is a container image, and if so, what format it's in. APK files are never
+// detected as images.
+func DetectImageFormat(p string) (ImageFormat, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return ImageFormatNone, err
+	}
+
+	if fi.IsDir() {
+		if _, err := os.Stat(filepath.Join(p, ociLayoutFile)); err == nil {
+			return ImageFormatOCILayout, nil
+		}
+
+		return ImageFormatNone, nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return ImageFormatNone, err
+	}
+	defer f.Close()
+
+	tr, err := newTarReader(f)
+	if err != nil {
+		return ImageFormatNone, err
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return ImageFormatNone, nil
+		}
+		if err != nil {
+			// Not a tar stream we can read, so not an image we can scan.
+			return ImageFormatNone, nil //nolint:nilerr
+		}
+
+		switch path.Clean(hdr.Name) {
+		case ociLayoutFile:
+			return ImageFormatOCIArchive, nil
+		case dockerManifestFile:
+			return ImageFormatTarball, nil
+		case pkginfoPath:
+			// This is an APK, no need to read any further.
+			return ImageFormatNone, nil
+		}
+	}
+}
+
+// GenerateFromImage creates an SBOM for the container image at the given path,
+// which must be in one of the formats recognized by DetectImageFormat. The
+// image's filesystem is flattened and cataloged, including its installed APK
+// database, so that each package can be attributed to the APK that owns it.
+//
+// If the image doesn't declare its Linux distribution (via /etc/os-release),
+// the given distroID is used.
+func GenerateFromImage(inputFilePath, distroID string) (*sbom.SBOM, error) {
+	tempDir, err := os.MkdirTemp("", "wolfictl-sbom-image-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	img, err := loadImage(inputFilePath, filepath.Join(tempDir, "image"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image: %w", err)
+	}
+
+	// Images can be much larger than APKs, so the flattened filesystem is
+	// extracted to disk rather than read into memory.
+	rootfs := filepath.Join(tempDir, "rootfs")
+	rc := mutate.Extract(img)
+	defer rc.Close()
+	if err := wtar.UntarUncompressed(rc, rootfs); err != nil {
+		return nil, fmt.Errorf("failed to unpack image filesystem: %w", err)
+	}
+
+	src, err := source.NewFromDirectory(
+		source.DirectoryConfig{
+			Path: rootfs,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source from directory: %w", err)
+	}
+
+	cfg := cataloger.DefaultConfig()
+	cfg.Catalogers = syftCatalogersEnabled
+
+	packageCollection, relationships, release, err := syft.CatalogPackages(src, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to catalog packages: %w", err)
+	}
+
+	if release == nil || release.ID == "" {
+		release = &linux.Release{
+			ID: distroID,
+		}
+	}
+
+	description, err := getImageSourceDescription(img, inputFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := sbom.SBOM{
+		Artifacts: sbom.Artifacts{
+			Packages:          packageCollection,
+			LinuxDistribution: release,
+		},
+		Relationships: relationships,
+		Source:        description,
+		Descriptor: sbom.Descriptor{
+			Name: "wolfictl",
+		},
+	}
+
+	return &s, nil
+}
+
+func loadImage(p, scratchDir string) (v1.Image, error) {
+	format, err := DetectImageFormat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case ImageFormatOCILayout:
+		return imageFromLayout(p)
+
+	case ImageFormatOCIArchive:
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		r, err := decompressed(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := wtar.UntarUncompressed(r, scratchDir); err != nil {
+			return nil, fmt.Errorf("failed to unpack OCI archive: %w", err)
+		}
+
+		return imageFromLayout(scratchDir)
+
+	case ImageFormatTarball:
+		return tarball.Image(func() (io.ReadCloser, error) {
+			f, err := os.Open(p)
+			if err != nil {
+				return nil, err
+			}
+
+			r, err := decompressed(f)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+
+			return readCloser{Reader: r, Closer: f}, nil
+		}, nil)
+	}
+
+	return nil, fmt.Errorf("%q is not a container image", p)
+}
+
+// imageFromLayout returns the image from the OCI layout at the given path. If
+// the layout contains more than one image (e.g. a multi-arch image), the image
+// for the current platform is used.
+func imageFromLayout(p string) (v1.Image, error) {
+	idx, err := layout.ImageIndexFromPath(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OCI layout: %w", err)
+	}
+
+	images, err := collectImages(idx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(images) == 1 {
+		return images[0].image, nil
+	}
+
+	want := v1.Platform{OS: "linux", Architecture: runtime.GOARCH}
+	for _, candidate := range images {
+		if candidate.platform != nil && candidate.platform.Satisfies(want) {
+			return candidate.image, nil
+		}
+	}
+
+	return nil, fmt.Errorf("OCI layout contains %d images, but none for platform %s", len(images), want.String())
+}
+
+type platformImage struct {
+	image    v1.Image
+	platform *v1.Platform
+}
+
+func collectImages(idx v1.ImageIndex) ([]platformImage, error) {
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image index: %w", err)
+	}
+
+	var images []platformImage
+	for _, desc := range manifest.Manifests {
+		switch {
+		case desc.MediaType.IsImage():
+			img, err := idx.Image(desc.Digest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read image %s: %w", desc.Digest, err)
+			}
+			images = append(images, platformImage{image: img, platform: desc.Platform})
+
+		case desc.MediaType.IsIndex():
+			child, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read image index %s: %w", desc.Digest, err)
+			}
+			childImages, err := collectImages(child)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, childImages...)
+		}
+	}
+
+	if len(images) == 0 {
+		return nil, errors.New("no images found in OCI layout")
+	}
+
+	return images, nil
+}
+
+func getImageSourceDescription(img v1.Image, inputFilePath string) (source.Description, error) {
+	digest, err := img.Digest()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to get image digest: %w", err)
+	}
+	configName, err := img.ConfigName()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to get image config digest: %w", err)
+	}
+	mediaType, err := img.MediaType()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to get image media type: %w", err)
+	}
+	rawManifest, err := img.RawManifest()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to get image manifest: %w", err)
+	}
+	rawConfig, err := img.RawConfigFile()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to get image config: %w", err)
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to parse image config: %w", err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return source.Description{}, fmt.Errorf("failed to parse image manifest: %w", err)
+	}
+
+	metadata := source.StereoscopeImageSourceMetadata{
+		UserInput:      inputFilePath,
+		ID:             configName.String(),
+		ManifestDigest: digest.String(),
+		MediaType:      string(mediaType),
+		RawManifest:    rawManifest,
+		RawConfig:      rawConfig,
+		Architecture:   configFile.Architecture,
+		Variant:        configFile.Variant,
+		OS:             configFile.OS,
+	}
+	for _, l := range manifest.Layers {
+		metadata.Size += l.Size
+		metadata.Layers = append(metadata.Layers, source.StereoscopeLayerMetadata{
+			MediaType: string(l.MediaType),
+			Digest:    l.Digest.String(),
+			Size:      l.Size,
+		})
+	}
+
+	return source.Description{
+		ID:       digest.String(),
+		Name:     inputFilePath,
+		Metadata: metadata,
+	}, nil
+}
+
+// newTarReader returns a tar reader for the given stream, transparently
+// decompressing it if it's gzip-compressed.
+func newTarReader(r io.Reader) (*tar.Reader, error) {
+	dr, err := decompressed(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return tar.NewReader(dr), nil
+}
+
+// decompressed returns a reader of the decompressed data if the given stream is
+// gzip-compressed, and otherwise returns a reader of the stream as-is.
+func decompressed(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(br)
+	}
+
+	return br, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
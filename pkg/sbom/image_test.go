@@ -0,0 +1,144 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const installedDB = `C:Q1Deb0jNytkrjPW4N/eKLZ43BwOlw=
+P:busybox
+V:1.36.1-r0
+A:x86_64
+o:busybox
+L:GPL-2.0-only
+F:bin
+R:busybox
+
+C:Q1Deb0jNytkrjPW4N/eKLZ43BwOlx=
+P:ko
+V:0.13.0-r2
+A:x86_64
+o:ko
+L:Apache-2.0
+F:usr/bin
+R:ko
+
+`
+
+const osRelease = `ID=wolfi
+NAME="Wolfi"
+PRETTY_NAME="Wolfi"
+VERSION_ID="20230201"
+`
+
+func TestDetectImageFormat(t *testing.T) {
+	dir := t.TempDir()
+	img := testImage(t)
+
+	layoutDir := filepath.Join(dir, "layout")
+	p, err := layout.Write(layoutDir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendImage(img))
+
+	tarballPath := filepath.Join(dir, "image.tar")
+	require.NoError(t, tarball.WriteToFile(tarballPath, name.MustParseReference("example.com/test:latest"), img))
+
+	cases := []struct {
+		path     string
+		expected ImageFormat
+	}{
+		{path: layoutDir, expected: ImageFormatOCILayout},
+		{path: tarballPath, expected: ImageFormatTarball},
+		{path: dir, expected: ImageFormatNone},
+		{path: filepath.Join("..", "tar", "testdata", "hello-wolfi-2.12-r1.apk"), expected: ImageFormatNone},
+	}
+
+	for _, tt := range cases {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			format, err := DetectImageFormat(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestGenerateFromImage(t *testing.T) {
+	layoutDir := filepath.Join(t.TempDir(), "layout")
+	p, err := layout.Write(layoutDir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendImage(testImage(t)))
+
+	s, err := GenerateFromImage(layoutDir, "fallback")
+	require.NoError(t, err)
+
+	require.NotNil(t, s.Artifacts.LinuxDistribution)
+	assert.Equal(t, "wolfi", s.Artifacts.LinuxDistribution.ID)
+
+	metadata, ok := s.Source.Metadata.(source.StereoscopeImageSourceMetadata)
+	require.True(t, ok)
+	assert.Equal(t, layoutDir, metadata.UserInput)
+	assert.NotEmpty(t, metadata.ManifestDigest)
+
+	apks := s.Artifacts.Packages.Sorted(pkg.ApkPkg)
+	require.Len(t, apks, 2)
+	assert.Equal(t, "busybox", apks[0].Name)
+	assert.Equal(t, "ko", apks[1].Name)
+
+	// The SBOM must survive a round trip through the cache's format.
+	r, err := ToSyftJSON(s)
+	require.NoError(t, err)
+	decoded, err := FromSyftJSON(r)
+	require.NoError(t, err)
+	_, ok = decoded.Source.Metadata.(source.StereoscopeImageSourceMetadata)
+	assert.True(t, ok)
+}
+
+func testImage(t *testing.T) v1.Image {
+	t.Helper()
+
+	files := map[string]string{
+		"lib/apk/db/installed": installedDB,
+		"etc/os-release":       osRelease,
+		"bin/busybox":          "busybox",
+		"usr/bin/ko":           "ko",
+	}
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, name := range []string{"bin/busybox", "etc/os-release", "lib/apk/db/installed", "usr/bin/ko"} {
+		content := files[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	require.NoError(t, err)
+
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	require.NoError(t, err)
+
+	return img
+}
@@ -0,0 +1,289 @@
+package sbom
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	stereoscopeFile "github.com/anchore/stereoscope/pkg/file"
+	"github.com/anchore/stereoscope/pkg/filetree"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/source"
+	wtar "github.com/wolfi-dev/wolfictl/pkg/tar"
+)
+
+// tarSource is a Syft source for the contents of a tar archive that's been read
+// into memory, such as an APK, so that it can be cataloged without extracting it
+// to disk.
+type tarSource struct {
+	description source.Description
+	resolver    *tarResolver
+}
+
+var _ source.Source = (*tarSource)(nil)
+
+func newTarSource(fsys *wtar.FS, description source.Description) (*tarSource, error) {
+	resolver, err := newTarResolver(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tarSource{
+		description: description,
+		resolver:    resolver,
+	}, nil
+}
+
+func (s *tarSource) ID() artifact.ID {
+	return artifact.ID(s.description.ID)
+}
+
+func (s *tarSource) FileResolver(_ source.Scope) (file.Resolver, error) {
+	return s.resolver, nil
+}
+
+func (s *tarSource) Describe() source.Description {
+	return s.description
+}
+
+func (s *tarSource) Close() error {
+	return nil
+}
+
+// tarResolver is a Syft file resolver for the contents of a tar archive. It
+// indexes the archive's files the same way Syft's directory resolver indexes a
+// directory, and it reports paths relative to the archive's root, so that the
+// resulting SBOMs are the same as those from cataloging the extracted archive.
+type tarResolver struct {
+	fsys          *wtar.FS
+	tree          *filetree.FileTree
+	index         filetree.Index
+	searchContext filetree.Searcher
+}
+
+var _ file.Resolver = (*tarResolver)(nil)
+
+func newTarResolver(fsys *wtar.FS) (*tarResolver, error) {
+	tree := filetree.New()
+	index := filetree.NewIndex()
+
+	err := fs.WalkDir(fsys, ".", func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+
+		info, err := fsys.Lstat(p)
+		if err != nil {
+			return err
+		}
+		header, ok := info.Sys().(*tar.Header)
+		if !ok {
+			return fmt.Errorf("unexpected file info for %s: %T", p, info.Sys())
+		}
+
+		realPath := stereoscopeFile.Path("/" + p)
+
+		var ref *stereoscopeFile.Reference
+		var content io.Reader
+		switch header.Typeflag {
+		case tar.TypeDir:
+			ref, err = tree.AddDir(realPath)
+		case tar.TypeSymlink:
+			ref, err = tree.AddSymLink(realPath, stereoscopeFile.Path(header.Linkname))
+		default:
+			ref, err = tree.AddFile(realPath)
+			if err == nil {
+				var f fs.File
+				f, err = fsys.Open(p)
+				if err == nil {
+					defer f.Close()
+					content = f
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to index %s: %w", p, err)
+		}
+
+		// A hardlink's header is its target's, so record the path it was found at.
+		h := *header
+		h.Name = p
+		index.Add(*ref, stereoscopeFile.NewMetadata(h, content))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index archive contents: %w", err)
+	}
+
+	return &tarResolver{
+		fsys:          fsys,
+		tree:          tree,
+		index:         index,
+		searchContext: filetree.NewSearchContext(tree, index),
+	}, nil
+}
+
+// requestPath returns the path in the file tree for the given path, which is
+// interpreted relative to the archive's root, whether or not it's absolute.
+func (r *tarResolver) requestPath(userPath string) string {
+	return path.Clean("/" + userPath)
+}
+
+// responsePath returns the given path from the file tree relative to the
+// archive's root, which is how Syft's directory resolver reports paths.
+func (r *tarResolver) responsePath(p string) string {
+	return strings.TrimPrefix(p, "/")
+}
+
+func (r *tarResolver) HasPath(userPath string) bool {
+	return r.tree.HasPath(stereoscopeFile.Path(r.requestPath(userPath)))
+}
+
+func (r *tarResolver) FilesByPath(userPaths ...string) ([]file.Location, error) {
+	var locations = make([]file.Location, 0)
+
+	for _, userPath := range userPaths {
+		requestPath := r.requestPath(userPath)
+
+		ref, err := r.searchContext.SearchByPath(requestPath, filetree.FollowBasenameLinks)
+		if err != nil || !ref.HasReference() {
+			continue
+		}
+
+		entry, err := r.index.Get(*ref.Reference)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get file by path=%q: %w", userPath, err)
+		}
+
+		// don't consider directories
+		if entry.Metadata.IsDir() {
+			continue
+		}
+
+		locations = append(locations, file.NewVirtualLocationFromDirectory(
+			r.responsePath(string(ref.RealPath)),
+			r.responsePath(requestPath),
+			*ref.Reference,
+		))
+	}
+
+	return locations, nil
+}
+
+func (r *tarResolver) FilesByGlob(patterns ...string) ([]file.Location, error) {
+	uniqueFileIDs := stereoscopeFile.NewFileReferenceSet()
+	locations := make([]file.Location, 0)
+
+	for _, pattern := range patterns {
+		refVias, err := r.searchContext.SearchByGlob(pattern, filetree.FollowBasenameLinks)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, refVia := range refVias {
+			if !refVia.HasReference() || uniqueFileIDs.Contains(*refVia.Reference) {
+				continue
+			}
+
+			entry, err := r.index.Get(*refVia.Reference)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get file metadata for reference %s: %w", refVia.Reference.RealPath, err)
+			}
+
+			// don't consider directories
+			if entry.Metadata.IsDir() {
+				continue
+			}
+
+			uniqueFileIDs.Add(*refVia.Reference)
+			locations = append(locations, file.NewVirtualLocationFromDirectory(
+				r.responsePath(string(refVia.Reference.RealPath)),
+				r.responsePath(string(refVia.RequestPath)),
+				*refVia.Reference,
+			))
+		}
+	}
+
+	return locations, nil
+}
+
+func (r *tarResolver) FilesByMIMEType(types ...string) ([]file.Location, error) {
+	uniqueFileIDs := stereoscopeFile.NewFileReferenceSet()
+	locations := make([]file.Location, 0)
+
+	refVias, err := r.searchContext.SearchByMIMEType(types...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, refVia := range refVias {
+		if !refVia.HasReference() || uniqueFileIDs.Contains(*refVia.Reference) {
+			continue
+		}
+
+		uniqueFileIDs.Add(*refVia.Reference)
+		locations = append(locations, file.NewLocationFromDirectory(
+			r.responsePath(string(refVia.Reference.RealPath)),
+			*refVia.Reference,
+		))
+	}
+
+	return locations, nil
+}
+
+func (r *tarResolver) RelativeFileByPath(_ file.Location, p string) *file.Location {
+	locations, err := r.FilesByPath(p)
+	if err != nil || len(locations) == 0 {
+		return nil
+	}
+
+	return &locations[0]
+}
+
+func (r *tarResolver) FileContentsByLocation(location file.Location) (io.ReadCloser, error) {
+	if location.RealPath == "" {
+		return nil, errors.New("empty path given")
+	}
+
+	entry, err := r.index.Get(location.Reference())
+	if err != nil {
+		return nil, err
+	}
+
+	// don't consider directories
+	if entry.Type == stereoscopeFile.TypeDirectory {
+		return nil, fmt.Errorf("cannot read contents of non-file %q", location.Reference().RealPath)
+	}
+
+	return r.fsys.Open(r.responsePath(string(location.Reference().RealPath)))
+}
+
+func (r *tarResolver) AllLocations() <-chan file.Location {
+	results := make(chan file.Location)
+	go func() {
+		defer close(results)
+		for _, ref := range r.tree.AllFiles(stereoscopeFile.AllTypes()...) {
+			results <- file.NewLocationFromDirectory(r.responsePath(string(ref.RealPath)), ref)
+		}
+	}()
+
+	return results
+}
+
+func (r *tarResolver) FileMetadataByLocation(location file.Location) (file.Metadata, error) {
+	entry, err := r.index.Get(location.Reference())
+	if err != nil {
+		return file.Metadata{}, fmt.Errorf("location: %+v: %w", location, fs.ErrNotExist)
+	}
+
+	return entry.Metadata, nil
+}
@@ -0,0 +1,121 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	wtar "github.com/wolfi-dev/wolfictl/pkg/tar"
+)
+
+func TestAPKResolver(t *testing.T) {
+	r := newTestTarResolver(t, map[string]string{
+		".PKGINFO":                "pkgname = foo\n",
+		"usr/lib/libfoo.so.1.2.3": "\x7fELF",
+		"usr/share/doc/README":    "hello",
+	}, map[string]string{
+		"usr/lib/libfoo.so": "libfoo.so.1.2.3",
+	})
+
+	t.Run("FilesByPath follows symlinks", func(t *testing.T) {
+		locations, err := r.FilesByPath("/usr/lib/libfoo.so")
+		require.NoError(t, err)
+		require.Len(t, locations, 1)
+
+		assert.Equal(t, "usr/lib/libfoo.so.1.2.3", locations[0].RealPath)
+		assert.Equal(t, "usr/lib/libfoo.so", locations[0].VirtualPath)
+
+		rc, err := r.FileContentsByLocation(locations[0])
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "\x7fELF", string(data))
+	})
+
+	t.Run("FilesByPath skips directories", func(t *testing.T) {
+		locations, err := r.FilesByPath("usr/share/doc")
+		require.NoError(t, err)
+		assert.Empty(t, locations)
+	})
+
+	t.Run("FilesByGlob", func(t *testing.T) {
+		locations, err := r.FilesByGlob("**/README")
+		require.NoError(t, err)
+		require.Len(t, locations, 1)
+		assert.Equal(t, "usr/share/doc/README", locations[0].RealPath)
+	})
+
+	t.Run("HasPath", func(t *testing.T) {
+		assert.True(t, r.HasPath("/.PKGINFO"))
+		assert.True(t, r.HasPath("usr/lib/libfoo.so"))
+		assert.False(t, r.HasPath("/etc/passwd"))
+	})
+
+	t.Run("FileMetadataByLocation", func(t *testing.T) {
+		locations, err := r.FilesByPath("usr/share/doc/README")
+		require.NoError(t, err)
+		require.Len(t, locations, 1)
+
+		metadata, err := r.FileMetadataByLocation(locations[0])
+		require.NoError(t, err)
+		assert.Equal(t, "/usr/share/doc/README", metadata.Path)
+		assert.Equal(t, int64(5), metadata.Size())
+		assert.Equal(t, "text/plain", metadata.MIMEType)
+	})
+
+	t.Run("AllLocations", func(t *testing.T) {
+		var paths []string
+		for l := range r.AllLocations() {
+			paths = append(paths, l.RealPath)
+		}
+		assert.Contains(t, paths, "usr/lib/libfoo.so")
+		assert.Contains(t, paths, "usr/share/doc/README")
+	})
+
+	t.Run("file digests", func(t *testing.T) {
+		digests, err := catalogFileDigests(r)
+		require.NoError(t, err)
+
+		var paths []string
+		for _, c := range sortedCoordinates(digests) {
+			paths = append(paths, c.RealPath)
+		}
+		assert.Equal(t, []string{"usr/lib/libfoo.so.1.2.3", "usr/share/doc/README"}, paths)
+		assert.Equal(t, []file.Digest{{
+			Algorithm: "sha256",
+			Value:     "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		}}, digests[file.Coordinates{RealPath: "usr/share/doc/README"}])
+	})
+}
+
+func newTestTarResolver(t *testing.T, files, symlinks map[string]string) *tarResolver {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(zw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	for name, target := range symlinks {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeSymlink, Linkname: target}))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	fsys, err := wtar.NewFS(buf)
+	require.NoError(t, err)
+
+	r, err := newTarResolver(fsys)
+	require.NoError(t, err)
+
+	return r
+}
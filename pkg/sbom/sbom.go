@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path"
 
 	"github.com/anchore/syft/syft"
 	"github.com/anchore/syft/syft/cpe"
@@ -39,21 +37,14 @@ var syftCatalogersEnabled = []string{
 
 // Generate creates an SBOM for the given APK file.
 func Generate(inputFilePath string, f io.Reader, distroID string) (*sbom.SBOM, error) {
-	// Create a temp directory to house the unpacked APK file
-	tempDir, err := os.MkdirTemp("", "wolfictl-sbom-*")
+	// Read the APK into memory, rather than extracting it to disk
+	fsys, err := tar.NewFS(f)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	// Unpack apk to temp directory
-	err = tar.Untar(f, tempDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unpack apk file: %w", err)
+		return nil, fmt.Errorf("failed to read apk file: %w", err)
 	}
 
 	// Analyze the APK metadata
-	pkginfo, err := os.Open(path.Join(tempDir, pkginfoPath))
+	pkginfo, err := fsys.Open(pkginfoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", pkginfoPath, err)
 	}
@@ -63,33 +54,48 @@ func Generate(inputFilePath string, f io.Reader, distroID string) (*sbom.SBOM, e
 		return nil, fmt.Errorf("failed to create APK package: %w", err)
 	}
 
-	src, err := source.NewFromDirectory(
-		source.DirectoryConfig{
-			Path: tempDir,
-		},
-	)
+	src, err := newTarSource(fsys, getDeterministicSourceDescription(inputFilePath))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create source from directory: %w", err)
+		return nil, fmt.Errorf("failed to create source from apk file: %w", err)
 	}
 
 	cfg := cataloger.DefaultConfig()
 	cfg.Catalogers = syftCatalogersEnabled
 
-	packageCollection, _, _, err := syft.CatalogPackages(src, cfg)
+	packageCollection, relationships, _, err := syft.CatalogPackages(src, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to catalog packages: %w", err)
 	}
 
+	// Record a digest of each file the APK installs, and the APK's ownership of
+	// those files.
+	resolver, err := src.FileResolver(source.SquashedScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file resolver: %w", err)
+	}
+	fileDigests, err := catalogFileDigests(resolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to catalog file digests: %w", err)
+	}
+	setAPKFiles(apkPackage, fileDigests)
+
 	packageCollection.Add(*apkPackage)
+	relationships = append(relationships, fileOwnershipRelationships(*apkPackage, fileDigests)...)
+
+	// Relate the APK to the cataloged packages it declares as dependencies. Its
+	// declared dependencies and provides are all in its metadata.
+	relationships = append(relationships, declaredDependencyRelationships(*apkPackage, packageCollection)...)
 
 	s := sbom.SBOM{
 		Artifacts: sbom.Artifacts{
-			Packages: packageCollection,
+			Packages:    packageCollection,
+			FileDigests: fileDigests,
 			LinuxDistribution: &linux.Release{
 				ID: distroID,
 			},
 		},
-		Source: getDeterministicSourceDescription(src, inputFilePath),
+		Relationships: relationships,
+		Source:        src.Describe(),
 		Descriptor: sbom.Descriptor{
 			Name: "wolfictl",
 		},
@@ -98,17 +104,14 @@ func Generate(inputFilePath string, f io.Reader, distroID string) (*sbom.SBOM, e
 	return &s, nil
 }
 
-func getDeterministicSourceDescription(src *source.DirectorySource, inputFilePath string) source.Description {
-	description := src.Describe()
-
-	description.ID = "(redacted for determinism)"
-	description.Name = inputFilePath
-	metadata := source.DirectorySourceMetadata{
-		Path: inputFilePath,
+func getDeterministicSourceDescription(inputFilePath string) source.Description {
+	return source.Description{
+		ID:   "(redacted for determinism)",
+		Name: inputFilePath,
+		Metadata: source.DirectorySourceMetadata{
+			Path: inputFilePath,
+		},
 	}
-	description.Metadata = metadata
-
-	return description
 }
 
 func newAPKPackage(r io.Reader, distroID string) (*pkg.Package, error) {
@@ -131,12 +134,20 @@ func newAPKPackage(r io.Reader, distroID string) (*pkg.Package, error) {
 		GitCommit:     pkginfo.Commit,
 	}
 
+	location := file.NewLocation(pkginfoPath)
+
+	// The license is parsed as an SPDX expression, where possible.
+	var licenses []pkg.License
+	if pkginfo.License != "" {
+		licenses = append(licenses, pkg.NewLicenseFromLocations(pkginfo.License, location))
+	}
+
 	p := pkg.Package{
 		Name:         pkginfo.PkgName,
 		Version:      pkginfo.PkgVer,
-		FoundBy:      "wolfictl",
-		Locations:    file.NewLocationSet(file.NewLocation(pkginfoPath)),
-		Licenses:     pkg.NewLicenseSet(pkg.NewLicense(pkginfo.License)),
+		FoundBy:      apkPackageFoundBy,
+		Locations:    file.NewLocationSet(location),
+		Licenses:     pkg.NewLicenseSet(licenses...),
 		Type:         pkg.ApkPkg,
 		MetadataType: pkg.ApkMetadataType,
 		Metadata:     metadata,
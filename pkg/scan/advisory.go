@@ -0,0 +1,98 @@
+package scan
+
+import (
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+// scannerName identifies this package's scanner in scan-based detection events.
+const scannerName = "grype"
+
+// AdvisoryRequests returns a request to create an advisory for each
+// vulnerability in the result that doesn't have an advisory yet. Findings that
+// FilterWithAdvisories would filter out using all advisories are skipped. Each
+// request's event is a detection event that records the component that
+// matched the vulnerability. When several findings for the same package share
+// a vulnerability, only the first one gets a request.
+func AdvisoryRequests(result *Result, advisoryCfgs *configs.Index[v2.Document]) ([]advisory.Request, error) {
+	findings, err := FilterWithAdvisories(result, advisoryCfgs, AdvisoriesSetAll)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var reqs []advisory.Request
+	for _, f := range findings {
+		target := result.TargetAPK
+		if f.APK != nil {
+			target = *f.APK
+		}
+		if target.Name == "" {
+			// The affected package isn't owned by any APK, so there's nowhere to
+			// record an advisory for it.
+			continue
+		}
+
+		// Advisories are recorded for the origin package, which covers all of its
+		// subpackages.
+		origin := target.Origin()
+		key := origin + "|" + f.Vulnerability.ID
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		reqs = append(reqs, advisory.Request{
+			Package:         origin,
+			VulnerabilityID: f.Vulnerability.ID,
+			Aliases:         validAliases(f.Vulnerability.Aliases),
+			Event:           DetectionEvent(target, *f),
+		})
+	}
+
+	return reqs, nil
+}
+
+// DetectionEvent returns an advisory detection event for the finding in the
+// given APK, which records the component that matched the vulnerability.
+func DetectionEvent(target TargetAPK, f Finding) v2.Event {
+	return v2.Event{
+		Timestamp: v2.Now(),
+		Type:      v2.EventTypeDetection,
+		Data:      Detection(target, f),
+	}
+}
+
+// Detection returns the scan-based detection data for the finding in the given
+// APK. If the APK is a subpackage, its name is recorded too.
+func Detection(target TargetAPK, f Finding) v2.Detection {
+	data := v2.DetectionScanV1{
+		ComponentID:       f.Package.ID,
+		ComponentName:     f.Package.Name,
+		ComponentVersion:  f.Package.Version,
+		ComponentType:     f.Package.Type,
+		ComponentLocation: f.Package.Location,
+		Scanner:           scannerName,
+	}
+	if target.Name != target.Origin() {
+		data.SubpackageName = target.Name
+	}
+
+	return v2.Detection{
+		Type: v2.DetectionTypeScanV1,
+		Data: data,
+	}
+}
+
+func validAliases(aliases []string) []string {
+	var valid []string
+	for _, alias := range aliases {
+		if vuln.ValidateID(alias) == nil {
+			valid = append(valid, alias)
+		}
+	}
+
+	return valid
+}
@@ -0,0 +1,102 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+)
+
+func TestAdvisoryRequests(t *testing.T) {
+	component := Package{
+		ID:       "5167656fc1b9f5e4",
+		Name:     "github.com/example/module",
+		Version:  "v1.2.3",
+		Type:     "go-module",
+		Location: "/usr/bin/ko",
+	}
+
+	result := &Result{
+		TargetAPK: TargetAPK{
+			Name:    "ko",
+			Version: "0.13.0-r3",
+		},
+		Findings: []*Finding{
+			// Covered by existing advisories.
+			{Package: component, Vulnerability: Vulnerability{ID: "CVE-1999-11111"}},
+			{Package: component, Vulnerability: Vulnerability{ID: "GHSA-abcd-efgh-ijkl", Aliases: []string{"CVE-2000-22222"}}},
+
+			// Not covered yet.
+			{Package: component, Vulnerability: Vulnerability{ID: "GHSA-2345-6789-cfgh", Aliases: []string{"CVE-2023-54321", "not-an-id"}}},
+			{Package: component, Vulnerability: Vulnerability{ID: "CVE-2023-12345"}},
+
+			// A second component matching the same vulnerability.
+			{Package: Package{Name: "other", Version: "1.0.0", Type: "binary", Location: "/usr/bin/other"}, Vulnerability: Vulnerability{ID: "CVE-2023-12345"}},
+		},
+	}
+
+	reqs, err := AdvisoryRequests(result, getAdvisoriesIndex(t))
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+
+	assert.Equal(t, "ko", reqs[0].Package)
+	assert.Equal(t, "GHSA-2345-6789-cfgh", reqs[0].VulnerabilityID)
+	assert.Equal(t, []string{"CVE-2023-54321"}, reqs[0].Aliases)
+
+	assert.Equal(t, "ko", reqs[1].Package)
+	assert.Equal(t, "CVE-2023-12345", reqs[1].VulnerabilityID)
+
+	for _, req := range reqs {
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, v2.EventTypeDetection, req.Event.Type)
+		assert.Equal(t, v2.Detection{
+			Type: v2.DetectionTypeScanV1,
+			Data: v2.DetectionScanV1{
+				ComponentID:       "5167656fc1b9f5e4",
+				ComponentName:     "github.com/example/module",
+				ComponentVersion:  "v1.2.3",
+				ComponentType:     "go-module",
+				ComponentLocation: "/usr/bin/ko",
+				Scanner:           "grype",
+			},
+		}, req.Event.Data)
+	}
+}
+
+func TestAdvisoryRequests_subpackage(t *testing.T) {
+	result := &Result{
+		TargetAPK: TargetAPK{
+			Name:              "ko-docs",
+			Version:           "0.13.0-r3",
+			OriginPackageName: "ko",
+		},
+		Findings: []*Finding{
+			// Covered by the origin package's advisories.
+			{Vulnerability: Vulnerability{ID: "CVE-1999-11111"}},
+
+			{
+				Package: Package{
+					Name:     "github.com/example/module",
+					Version:  "v1.2.3",
+					Type:     "go-module",
+					Location: "/usr/share/doc/ko/example",
+				},
+				Vulnerability: Vulnerability{ID: "CVE-2023-12345"},
+			},
+		},
+	}
+
+	reqs, err := AdvisoryRequests(result, getAdvisoriesIndex(t))
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+
+	assert.Equal(t, "ko", reqs[0].Package)
+	assert.Equal(t, "CVE-2023-12345", reqs[0].VulnerabilityID)
+
+	detection, ok := reqs[0].Event.Data.(v2.Detection)
+	require.True(t, ok)
+	data, ok := detection.Data.(v2.DetectionScanV1)
+	require.True(t, ok)
+	assert.Equal(t, "ko-docs", data.SubpackageName)
+}
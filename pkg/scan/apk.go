@@ -1,11 +1,13 @@
 package scan
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/adrg/xdg"
@@ -27,8 +29,11 @@ import (
 	"github.com/anchore/syft/syft/file"
 	"github.com/anchore/syft/syft/pkg"
 	sbomSyft "github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
 	"github.com/samber/lo"
 	"github.com/wolfi-dev/wolfictl/pkg/sbom"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
 )
 
 const grypeDBListingURL = "https://toolbox-data.anchore.io/grype/databases/listing.json"
@@ -45,12 +50,60 @@ var grypeDBConfig = db.Config{
 
 type Result struct {
 	TargetAPK TargetAPK
-	Findings  []*Finding
+
+	// TargetImage is set instead of TargetAPK when the scanned SBOM describes a
+	// container image.
+	TargetImage *TargetImage `json:",omitempty"`
+
+	Findings []*Finding
+
+	// Suppressed holds the findings that were filtered out because of an
+	// advisory, if advisory-based filtering was used.
+	Suppressed []SuppressedFinding `json:",omitempty"`
+
+	// VulnerabilityDB identifies the vulnerability database used for the scan.
+	VulnerabilityDB *DBInfo `json:",omitempty"`
 }
 
 type TargetAPK struct {
 	Name    string
 	Version string
+
+	// OriginPackageName is the name of the package that the APK was built from,
+	// per its .PKGINFO. It differs from Name for subpackages (e.g. "foo" for
+	// "foo-dev").
+	OriginPackageName string `json:",omitempty"`
+}
+
+// Origin returns the name of the package that the APK was built from, which is
+// the package that the APK's advisories are recorded for. If the origin isn't
+// known, the APK's own name is returned.
+func (t TargetAPK) Origin() string {
+	if t.OriginPackageName != "" {
+		return t.OriginPackageName
+	}
+
+	return t.Name
+}
+
+// newTargetAPKFromPackage returns the TargetAPK for the given syft APK package.
+func newTargetAPKFromPackage(p pkg.Package) TargetAPK {
+	apk := TargetAPK{
+		Name:    p.Name,
+		Version: p.Version,
+	}
+
+	if metadata, ok := p.Metadata.(pkg.ApkMetadata); ok && metadata.OriginPackage != p.Name {
+		apk.OriginPackageName = metadata.OriginPackage
+	}
+
+	return apk
+}
+
+// TargetImage identifies a scanned container image.
+type TargetImage struct {
+	Name   string
+	Digest string
 }
 
 func newTargetAPK(s *sbomSyft.SBOM) (TargetAPK, error) {
@@ -62,32 +115,40 @@ func newTargetAPK(s *sbomSyft.SBOM) (TargetAPK, error) {
 		return TargetAPK{}, fmt.Errorf("expected exactly one APK package, found %d", len(pkgs))
 	}
 
-	p := pkgs[0]
-
-	return TargetAPK{
-		Name:    p.Name,
-		Version: p.Version,
-	}, nil
+	return newTargetAPKFromPackage(pkgs[0]), nil
 }
 
-// APKSBOM scans an SBOM of an APK for vulnerabilities.
-func APKSBOM(r io.Reader, localDBFilePath string) (*Result, error) {
-	s, err := sbom.FromSyftJSON(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode Syft SBOM: %w", err)
-	}
-
-	return scan(s, localDBFilePath)
+// Scanner scans SBOMs for vulnerabilities. It loads the vulnerability database
+// once, so that it can be reused across many scans. Scanner is safe for
+// concurrent use.
+type Scanner struct {
+	datastore            *store.Store
+	dbCloser             *db.Closer
+	vulnerabilityMatcher *grype.VulnerabilityMatcher
+	dbInfo               DBInfo
+
+	// matchMu serializes vulnerability matching, since the underlying database
+	// connection isn't guaranteed to be safe for concurrent use.
+	matchMu sync.Mutex
 }
 
-func scan(s *sbomSyft.SBOM, localDBFilePath string) (*Result, error) {
-	apk, err := newTargetAPK(s)
+// NewScanner loads the vulnerability database and returns a Scanner that uses
+// it. If localDBFilePath is set, the database is first imported from that file
+// instead of being updated from the network. If a database is pinned, the
+// pinned database is used as-is, without being updated. Callers should call
+// Close when they're done with the Scanner.
+func NewScanner(localDBFilePath string) (*Scanner, error) {
+	pin, err := readDBPin()
 	if err != nil {
 		return nil, err
 	}
 
-	updateDB := true
+	updateDB := pin == nil
 	if localDBFilePath != "" {
+		if pin != nil {
+			return nil, errors.New("a local vulnerability database can't be used while a database is pinned, unpin it first")
+		}
+
 		fmt.Fprintf(os.Stderr, "Loading local grype DB %s...\n", localDBFilePath)
 		dbCurator, err := db.NewCurator(grypeDBConfig)
 		if err != nil {
@@ -101,21 +162,87 @@ func scan(s *sbomSyft.SBOM, localDBFilePath string) (*Result, error) {
 		updateDB = false
 	}
 
-	datastore, _, dbCloser, err := grype.LoadVulnerabilityDB(grypeDBConfig, updateDB)
+	datastore, status, dbCloser, err := grype.LoadVulnerabilityDB(dbConfig(pin), updateDB)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load vulnerability database: %w", err)
 	}
-	defer dbCloser.Close()
 
-	vulnerabilityMatcher := newGrypeVulnerabilityMatcher(*datastore)
+	if err := pin.check(status.Checksum); err != nil {
+		dbCloser.Close()
+		return nil, err
+	}
+
+	return &Scanner{
+		datastore:            datastore,
+		dbCloser:             dbCloser,
+		vulnerabilityMatcher: newGrypeVulnerabilityMatcher(*datastore),
+		dbInfo: DBInfo{
+			Built:         status.Built,
+			SchemaVersion: status.SchemaVersion,
+			Checksum:      status.Checksum,
+			Pinned:        pin != nil,
+		},
+	}, nil
+}
+
+// Close releases the Scanner's vulnerability database.
+func (s *Scanner) Close() {
+	if s.dbCloser != nil {
+		s.dbCloser.Close()
+	}
+}
+
+// APKSBOM scans an SBOM of an APK for vulnerabilities. The SBOM can also
+// describe a container image, in which case each finding is attributed to the
+// installed APK that owns the affected package.
+func (s *Scanner) APKSBOM(r io.Reader) (*Result, error) {
+	decoded, err := sbom.FromSyftJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Syft SBOM: %w", err)
+	}
+
+	return s.scan(decoded)
+}
+
+// APKSBOM scans an SBOM of an APK for vulnerabilities. It loads the
+// vulnerability database for just this scan; to scan many SBOMs, use a Scanner.
+func APKSBOM(r io.Reader, localDBFilePath string) (*Result, error) {
+	scanner, err := NewScanner(localDBFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer scanner.Close()
+
+	return scanner.APKSBOM(r)
+}
+
+func (s *Scanner) scan(sb *sbomSyft.SBOM) (*Result, error) {
+	var apk TargetAPK
+	var image *TargetImage
 
-	syftPkgs := s.Artifacts.Packages.Sorted()
+	if metadata, ok := sb.Source.Metadata.(source.StereoscopeImageSourceMetadata); ok {
+		image = &TargetImage{
+			Name:   metadata.UserInput,
+			Digest: metadata.ManifestDigest,
+		}
+	} else {
+		var err error
+		apk, err = newTargetAPK(sb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	syftPkgs := sb.Artifacts.Packages.Sorted()
 	grypePkgs := grypePkg.FromPackages(syftPkgs, grypePkg.SynthesisConfig{GenerateMissingCPEs: false})
 
 	// Find vulnerability matches
-	matchesCollection, _, err := vulnerabilityMatcher.FindMatches(grypePkgs, grypePkg.Context{
-		Source: &s.Source,
-		Distro: s.Artifacts.LinuxDistribution,
+	s.matchMu.Lock()
+	defer s.matchMu.Unlock()
+
+	matchesCollection, _, err := s.vulnerabilityMatcher.FindMatches(grypePkgs, grypePkg.Context{
+		Source: &sb.Source,
+		Distro: sb.Artifacts.LinuxDistribution,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find vulnerability matches: %w", err)
@@ -123,20 +250,30 @@ func scan(s *sbomSyft.SBOM, localDBFilePath string) (*Result, error) {
 
 	matches := matchesCollection.Sorted()
 
+	var owners apkOwnership
+	if image != nil {
+		owners = newAPKOwnership(syftPkgs)
+	}
+
 	var findings []*Finding
 	for i := range matches {
 		m := matches[i]
 
-		finding, err := mapMatchToFinding(m, datastore)
+		finding, err := mapMatchToFinding(m, s.datastore)
 		if err != nil {
 			return nil, fmt.Errorf("failed to map match to finding: %w", err)
 		}
+		if image != nil {
+			finding.APK = owners.ownerOf(m.Package)
+		}
 		findings = append(findings, finding)
 	}
 
 	result := &Result{
-		TargetAPK: apk,
-		Findings:  findings,
+		TargetAPK:       apk,
+		TargetImage:     image,
+		Findings:        findings,
+		VulnerabilityDB: &s.dbInfo,
 	}
 
 	return result, nil
@@ -146,6 +283,71 @@ func scan(s *sbomSyft.SBOM, localDBFilePath string) (*Result, error) {
 type Finding struct {
 	Package       Package
 	Vulnerability Vulnerability
+
+	// APK is the installed APK that owns the affected package. It's only set for
+	// findings from an image scan, and it's nil if no installed APK owns the
+	// package.
+	APK *TargetAPK `json:",omitempty"`
+
+	// Match explains how the vulnerability was matched to the package.
+	Match *Match `json:",omitempty"`
+}
+
+// Match explains how a vulnerability was matched to a package.
+type Match struct {
+	// Namespace is the vulnerability data namespace that the vulnerability was
+	// found in, e.g. "wolfi:distro:wolfi:rolling" or "nvd:cpe".
+	Namespace string
+
+	// Details lists the ways the vulnerability was matched to the package.
+	Details []MatchDetail
+}
+
+// MatchDetail describes one way a vulnerability was matched to a package.
+type MatchDetail struct {
+	// Matcher is the name of the matcher that made the match, e.g.
+	// "apk-matcher".
+	Matcher string
+
+	// Type is the kind of match: an exact match on the package itself
+	// ("exact-direct-match"), an exact match on a package it's related to, like
+	// its source package ("exact-indirect-match"), or a match on CPEs
+	// ("cpe-match").
+	Type string
+
+	// SearchedBy is the data used to search for vulnerabilities, other than the
+	// package name and version, e.g. the distro or the CPEs of the package.
+	SearchedBy any `json:",omitempty"`
+
+	// FoundBy is the data on the vulnerability record that matched, e.g. the
+	// record's version constraint and CPEs.
+	FoundBy any `json:",omitempty"`
+}
+
+// IsCPEMatch returns true if any of the match details is a CPE-based match,
+// which is much more likely to be a false positive than an exact match.
+func (m Match) IsCPEMatch() bool {
+	for _, d := range m.Details {
+		if d.Type == string(match.CPEMatch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newMatch(m match.Match) *Match {
+	return &Match{
+		Namespace: m.Vulnerability.Namespace,
+		Details: lo.Map(m.Details, func(d match.Detail, _ int) MatchDetail {
+			return MatchDetail{
+				Matcher:    string(d.Matcher),
+				Type:       string(d.Type),
+				SearchedBy: d.SearchedBy,
+				FoundBy:    d.Found,
+			}
+		}),
+	}
 }
 
 type Package struct {
@@ -161,6 +363,29 @@ type Vulnerability struct {
 	Severity     string
 	Aliases      []string
 	FixedVersion string
+
+	// CVSS lists the CVSS v3 and v4 assessments found in the metadata for the
+	// vulnerability and its related vulnerabilities (e.g. from NVD).
+	CVSS []vuln.CVSS `json:",omitempty"`
+
+	// Exploitability is the EPSS and KEV data for the vulnerability. It's only
+	// set if the finding was enriched using EnrichWithExploitability.
+	Exploitability *exploit.Exploitability `json:",omitempty"`
+}
+
+// HighestCVSSBaseScore returns the highest CVSS base score among the
+// vulnerability's CVSS assessments. The second return value is false if the
+// vulnerability has no CVSS assessments.
+func (v Vulnerability) HighestCVSSBaseScore() (float64, bool) {
+	if len(v.CVSS) == 0 {
+		return 0, false
+	}
+
+	highest := lo.MaxBy(v.CVSS, func(a, b vuln.CVSS) bool {
+		return a.BaseScore > b.BaseScore
+	})
+
+	return highest.BaseScore, true
 }
 
 func mapMatchToFinding(m match.Match, datastore *store.Store) (*Finding, error) {
@@ -202,18 +427,54 @@ func mapMatchToFinding(m match.Match, datastore *store.Store) (*Finding, error)
 			Severity:     metadata.Severity,
 			Aliases:      aliases,
 			FixedVersion: getFixedVersion(m.Vulnerability),
+			CVSS:         getCVSS(append([]*vulnerability.Metadata{metadata}, relatedMetadatas...)),
 		},
+		Match: newMatch(m),
 	}
 
 	return f, nil
 }
 
-func getFixedVersion(vuln vulnerability.Vulnerability) string {
-	if vuln.Fix.State != v5.FixedState {
+// getCVSS returns the CVSS v3 and v4 assessments from the given vulnerability
+// metadata, without duplicates.
+func getCVSS(metadatas []*vulnerability.Metadata) []vuln.CVSS {
+	var assessments []vuln.CVSS
+	seen := make(map[string]struct{})
+
+	for _, m := range metadatas {
+		if m == nil {
+			continue
+		}
+
+		for _, c := range m.Cvss {
+			if !strings.HasPrefix(c.Version, "3") && !strings.HasPrefix(c.Version, "4") {
+				continue
+			}
+
+			key := c.Source + "|" + c.Vector
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			assessments = append(assessments, vuln.CVSS{
+				Version:   c.Version,
+				Vector:    c.Vector,
+				BaseScore: c.Metrics.BaseScore,
+				Source:    c.Source,
+			})
+		}
+	}
+
+	return assessments
+}
+
+func getFixedVersion(v vulnerability.Vulnerability) string {
+	if v.Fix.State != v5.FixedState {
 		return ""
 	}
 
-	return strings.Join(vuln.Fix.Versions, ", ")
+	return strings.Join(v.Fix.Versions, ", ")
 }
 
 func newGrypeVulnerabilityMatcher(datastore store.Store) *grype.VulnerabilityMatcher {
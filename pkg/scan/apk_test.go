@@ -0,0 +1,144 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/google/go-cmp/cmp"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+func TestGetCVSS(t *testing.T) {
+	metadatas := []*vulnerability.Metadata{
+		{
+			ID: "GHSA-2qjp-425j-52j9",
+			Cvss: []vulnerability.Cvss{
+				{
+					Source:  "github",
+					Version: "3.1",
+					Vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+					Metrics: vulnerability.CvssMetrics{BaseScore: 9.8},
+				},
+			},
+		},
+		nil,
+		{
+			ID: "CVE-2023-39323",
+			Cvss: []vulnerability.Cvss{
+				{
+					Source:  "nvd@nist.gov",
+					Version: "2.0",
+					Vector:  "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+					Metrics: vulnerability.CvssMetrics{BaseScore: 7.5},
+				},
+				{
+					Source:  "nvd@nist.gov",
+					Version: "3.1",
+					Vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+					Metrics: vulnerability.CvssMetrics{BaseScore: 9.8},
+				},
+				{
+					Source:  "nvd@nist.gov",
+					Version: "3.1",
+					Vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+					Metrics: vulnerability.CvssMetrics{BaseScore: 9.8},
+				},
+			},
+		},
+	}
+
+	expected := []vuln.CVSS{
+		{
+			Version:   "3.1",
+			Vector:    "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			BaseScore: 9.8,
+			Source:    "github",
+		},
+		{
+			Version:   "3.1",
+			Vector:    "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			BaseScore: 9.8,
+			Source:    "nvd@nist.gov",
+		},
+	}
+
+	if diff := cmp.Diff(expected, getCVSS(metadatas)); diff != "" {
+		t.Errorf("getCVSS() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVulnerability_HighestCVSSBaseScore(t *testing.T) {
+	v := Vulnerability{
+		CVSS: []vuln.CVSS{
+			{BaseScore: 5.3},
+			{BaseScore: 8.1},
+			{BaseScore: 7.5},
+		},
+	}
+
+	score, ok := v.HighestCVSSBaseScore()
+	if !ok || score != 8.1 {
+		t.Errorf("HighestCVSSBaseScore() = %v, %v, want 8.1, true", score, ok)
+	}
+
+	if _, ok := (Vulnerability{}).HighestCVSSBaseScore(); ok {
+		t.Errorf("HighestCVSSBaseScore() for vulnerability without CVSS data should not be ok")
+	}
+}
+
+func TestNewMatch(t *testing.T) {
+	m := match.Match{
+		Vulnerability: vulnerability.Vulnerability{
+			ID:        "CVE-2023-1234",
+			Namespace: "nvd:cpe",
+		},
+		Details: match.Details{
+			{
+				Type:    match.CPEMatch,
+				Matcher: match.GoModuleMatcher,
+				SearchedBy: search.CPEParameters{
+					Namespace: "nvd:cpe",
+					CPEs:      []string{"cpe:2.3:a:example:server:1.0.0:*:*:*:*:*:*:*"},
+				},
+				Found: search.CPEResult{
+					VulnerabilityID:   "CVE-2023-1234",
+					VersionConstraint: "< 1.2.0 (semver)",
+					CPEs:              []string{"cpe:2.3:a:example:server:*:*:*:*:*:*:*:*"},
+				},
+			},
+		},
+	}
+
+	expected := &Match{
+		Namespace: "nvd:cpe",
+		Details: []MatchDetail{
+			{
+				Matcher: "go-module-matcher",
+				Type:    "cpe-match",
+				SearchedBy: search.CPEParameters{
+					Namespace: "nvd:cpe",
+					CPEs:      []string{"cpe:2.3:a:example:server:1.0.0:*:*:*:*:*:*:*"},
+				},
+				FoundBy: search.CPEResult{
+					VulnerabilityID:   "CVE-2023-1234",
+					VersionConstraint: "< 1.2.0 (semver)",
+					CPEs:              []string{"cpe:2.3:a:example:server:*:*:*:*:*:*:*:*"},
+				},
+			},
+		},
+	}
+
+	actual := newMatch(m)
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected match (-want +got):\n%s", diff)
+	}
+
+	if !actual.IsCPEMatch() {
+		t.Error("expected a CPE match")
+	}
+	if (Match{Details: []MatchDetail{{Type: "exact-direct-match"}}}).IsCPEMatch() {
+		t.Error("expected an exact match not to be a CPE match")
+	}
+}
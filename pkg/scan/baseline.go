@@ -0,0 +1,83 @@
+package scan
+
+import (
+	"sort"
+	"strings"
+)
+
+// Key returns a stable identity for the finding, for recognizing the same
+// finding across scans. It's made of the package name and type, the
+// vulnerability ID, and the package location. The package version and ID aren't
+// included, since the ID is a hash of the package's version and metadata, and
+// both change whenever the package is rebuilt or updated.
+func (f Finding) Key() string {
+	return strings.Join([]string{f.Package.Name, f.Package.Type, f.Vulnerability.ID, f.Package.Location}, "|")
+}
+
+// Comparison classifies the findings of a scan relative to the findings of an
+// earlier (baseline) scan.
+type Comparison struct {
+	// New are the findings that weren't in the baseline.
+	New []*Finding
+
+	// Fixed are the baseline findings that are no longer found.
+	Fixed []*Finding
+
+	// Unchanged are the findings that were also in the baseline.
+	Unchanged []*Finding
+}
+
+// Compare classifies the current findings relative to the baseline findings,
+// using each finding's Key to identify it.
+func Compare(baseline, current []*Finding) Comparison {
+	baselineKeys := make(map[string]struct{}, len(baseline))
+	for _, f := range baseline {
+		baselineKeys[f.Key()] = struct{}{}
+	}
+
+	currentKeys := make(map[string]struct{}, len(current))
+	c := Comparison{
+		New:       []*Finding{},
+		Fixed:     []*Finding{},
+		Unchanged: []*Finding{},
+	}
+
+	for _, f := range current {
+		key := f.Key()
+		if _, ok := currentKeys[key]; ok {
+			continue
+		}
+		currentKeys[key] = struct{}{}
+
+		if _, ok := baselineKeys[key]; ok {
+			c.Unchanged = append(c.Unchanged, f)
+		} else {
+			c.New = append(c.New, f)
+		}
+	}
+
+	fixedKeys := make(map[string]struct{})
+	for _, f := range baseline {
+		key := f.Key()
+		if _, ok := currentKeys[key]; ok {
+			continue
+		}
+		if _, ok := fixedKeys[key]; ok {
+			continue
+		}
+		fixedKeys[key] = struct{}{}
+		c.Fixed = append(c.Fixed, f)
+	}
+
+	for _, findings := range [][]*Finding{c.New, c.Fixed, c.Unchanged} {
+		sortByKey(findings)
+	}
+
+	return c
+}
+
+func sortByKey(findings []*Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Key() < findings[j].Key()
+	})
+}
@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	finding := func(packageName, vulnID, location string) *Finding {
+		return &Finding{
+			Package:       Package{Name: packageName, Type: "binary", Location: location},
+			Vulnerability: Vulnerability{ID: vulnID},
+		}
+	}
+
+	baseline := []*Finding{
+		finding("pkg-a", "CVE-2023-0001", "/usr/bin/a"),
+		finding("pkg-a", "CVE-2023-0002", "/usr/bin/a"),
+		finding("pkg-b", "CVE-2023-0003", "/usr/bin/b"),
+	}
+
+	current := []*Finding{
+		finding("pkg-a", "CVE-2023-0001", "/usr/bin/a"),
+		// Same package and vulnerability, but at a different location.
+		finding("pkg-b", "CVE-2023-0003", "/usr/lib/b"),
+		finding("pkg-c", "CVE-2023-0004", "/usr/bin/c"),
+		// Duplicates are only counted once.
+		finding("pkg-c", "CVE-2023-0004", "/usr/bin/c"),
+	}
+
+	c := Compare(baseline, current)
+
+	assert.Equal(t, []*Finding{
+		finding("pkg-b", "CVE-2023-0003", "/usr/lib/b"),
+		finding("pkg-c", "CVE-2023-0004", "/usr/bin/c"),
+	}, c.New)
+	assert.Equal(t, []*Finding{
+		finding("pkg-a", "CVE-2023-0002", "/usr/bin/a"),
+		finding("pkg-b", "CVE-2023-0003", "/usr/bin/b"),
+	}, c.Fixed)
+	assert.Equal(t, []*Finding{
+		finding("pkg-a", "CVE-2023-0001", "/usr/bin/a"),
+	}, c.Unchanged)
+}
+
+func TestCompare_emptyBaseline(t *testing.T) {
+	current := []*Finding{
+		{Package: Package{Name: "pkg-a"}, Vulnerability: Vulnerability{ID: "CVE-2023-0001"}},
+	}
+
+	c := Compare(nil, current)
+
+	assert.Equal(t, current, c.New)
+	assert.Empty(t, c.Fixed)
+	assert.Empty(t, c.Unchanged)
+}
+
+func TestCompare_rebuiltPackage(t *testing.T) {
+	baseline := []*Finding{
+		{
+			Package:       Package{ID: "0123456789abcdef", Name: "stdlib", Version: "go1.21.3", Type: "go-module", Location: "/usr/bin/ko"},
+			Vulnerability: Vulnerability{ID: "CVE-2023-39325", Severity: "High"},
+		},
+	}
+
+	// The same finding, after the package was rebuilt with a newer version, which
+	// changes its ID (a hash of its version and metadata).
+	current := []*Finding{
+		{
+			Package:       Package{ID: "fedcba9876543210", Name: "stdlib", Version: "go1.21.4", Type: "go-module", Location: "/usr/bin/ko"},
+			Vulnerability: Vulnerability{ID: "CVE-2023-39325", Severity: "High"},
+		},
+		// A package of a different type with the same name is a different finding.
+		{
+			Package:       Package{ID: "0011223344556677", Name: "stdlib", Version: "1.0.0", Type: "binary", Location: "/usr/bin/ko"},
+			Vulnerability: Vulnerability{ID: "CVE-2023-39325", Severity: "High"},
+		},
+	}
+
+	c := Compare(baseline, current)
+
+	assert.Equal(t, []*Finding{current[0]}, c.Unchanged)
+	assert.Equal(t, []*Finding{current[1]}, c.New)
+	assert.Empty(t, c.Fixed)
+}
@@ -0,0 +1,235 @@
+package scan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+)
+
+// dbPinPath is the location of the file that records the pinned vulnerability
+// database, if any.
+var dbPinPath = path.Join(path.Dir(grypeDBDir), "pin.json")
+
+// DBInfo identifies the vulnerability database used for a scan.
+type DBInfo struct {
+	// Built is when the database was built.
+	Built time.Time
+
+	// SchemaVersion is the grype database schema version.
+	SchemaVersion int
+
+	// Checksum is the checksum of the database file, which uniquely identifies
+	// the database.
+	Checksum string
+
+	// Pinned is true if the database was pinned when the scan was performed.
+	Pinned bool
+}
+
+// DBStatus describes the locally cached vulnerability database.
+type DBStatus struct {
+	DBInfo
+
+	// Location is the directory that holds the database.
+	Location string
+
+	// Pin describes the pinned database, if any.
+	Pin *DBPin `json:",omitempty"`
+
+	// Err describes why the database can't be used, if it can't.
+	Err string `json:",omitempty"`
+}
+
+// DBPin records the vulnerability database that scans must use. While a
+// database is pinned, it's never updated automatically, and it's used
+// regardless of its age, so that scans are reproducible and can run offline.
+type DBPin struct {
+	Built         time.Time
+	SchemaVersion int
+	Checksum      string
+	PinnedAt      time.Time
+}
+
+// GetDBStatus returns the status of the locally cached vulnerability database.
+func GetDBStatus() (*DBStatus, error) {
+	pin, err := readDBPin()
+	if err != nil {
+		return nil, err
+	}
+
+	curator, err := db.NewCurator(dbConfig(pin))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the grype db curator: %w", err)
+	}
+
+	status := curator.Status()
+	s := &DBStatus{
+		DBInfo: DBInfo{
+			Built:         status.Built,
+			SchemaVersion: status.SchemaVersion,
+			Checksum:      status.Checksum,
+			Pinned:        pin != nil,
+		},
+		Location: status.Location,
+		Pin:      pin,
+	}
+
+	if status.Err != nil {
+		s.Err = status.Err.Error()
+	} else if err := pin.check(status.Checksum); err != nil {
+		s.Err = err.Error()
+	}
+
+	return s, nil
+}
+
+// UpdateDB updates the locally cached vulnerability database to the latest
+// available version. It returns true if a newer database was downloaded. It
+// fails if a database is pinned.
+func UpdateDB() (bool, error) {
+	pin, err := readDBPin()
+	if err != nil {
+		return false, err
+	}
+	if pin != nil {
+		return false, errors.New("the vulnerability database is pinned, unpin it before updating")
+	}
+
+	curator, err := db.NewCurator(grypeDBConfig)
+	if err != nil {
+		return false, fmt.Errorf("unable to create the grype db curator: %w", err)
+	}
+
+	updated, err := curator.Update()
+	if err != nil {
+		return false, fmt.Errorf("unable to update vulnerability database: %w", err)
+	}
+
+	return updated, nil
+}
+
+// ImportDB imports a vulnerability database archive (as published by grype)
+// into the local cache, replacing the current database. It fails if a
+// database is pinned.
+func ImportDB(archivePath string) error {
+	pin, err := readDBPin()
+	if err != nil {
+		return err
+	}
+	if pin != nil {
+		return errors.New("the vulnerability database is pinned, unpin it before importing another database")
+	}
+
+	curator, err := db.NewCurator(grypeDBConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create the grype db curator: %w", err)
+	}
+
+	if err := curator.ImportFrom(archivePath); err != nil {
+		return fmt.Errorf("unable to import vulnerability database: %w", err)
+	}
+
+	return nil
+}
+
+// PinDB pins the locally cached vulnerability database, so that scans use it
+// until it's unpinned.
+func PinDB() (*DBPin, error) {
+	curator, err := db.NewCurator(dbConfig(&DBPin{}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the grype db curator: %w", err)
+	}
+
+	status := curator.Status()
+	if status.Err != nil {
+		return nil, fmt.Errorf("unable to pin vulnerability database: %w", status.Err)
+	}
+
+	pin := &DBPin{
+		Built:         status.Built,
+		SchemaVersion: status.SchemaVersion,
+		Checksum:      status.Checksum,
+		PinnedAt:      time.Now().UTC(),
+	}
+
+	if err := writeDBPin(pin); err != nil {
+		return nil, err
+	}
+
+	return pin, nil
+}
+
+// UnpinDB removes the pin on the vulnerability database, if any.
+func UnpinDB() error {
+	err := os.Remove(dbPinPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to unpin vulnerability database: %w", err)
+	}
+
+	return nil
+}
+
+// dbConfig returns the grype DB config to use, given the current pin. A pinned
+// database must be usable regardless of its age.
+func dbConfig(pin *DBPin) db.Config {
+	cfg := grypeDBConfig
+	if pin != nil {
+		cfg.ValidateAge = false
+	}
+
+	return cfg
+}
+
+func readDBPin() (*DBPin, error) {
+	b, err := os.ReadFile(dbPinPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read vulnerability database pin: %w", err)
+	}
+
+	pin := &DBPin{}
+	if err := json.Unmarshal(b, pin); err != nil {
+		return nil, fmt.Errorf("unable to decode vulnerability database pin (%s): %w", dbPinPath, err)
+	}
+
+	return pin, nil
+}
+
+func writeDBPin(pin *DBPin) error {
+	b, err := json.MarshalIndent(pin, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(dbPinPath), 0o755); err != nil {
+		return fmt.Errorf("unable to create vulnerability database directory: %w", err)
+	}
+
+	if err := os.WriteFile(dbPinPath, b, 0o600); err != nil {
+		return fmt.Errorf("unable to write vulnerability database pin: %w", err)
+	}
+
+	return nil
+}
+
+// check returns an error if the database with the given checksum isn't the
+// pinned database. A nil pin accepts any database.
+func (pin *DBPin) check(checksum string) error {
+	if pin == nil || pin.Checksum == checksum {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"the cached vulnerability database (%s) isn't the pinned database (%s, built %s), import the pinned database or unpin it",
+		checksum,
+		pin.Checksum,
+		pin.Built.Format(time.RFC3339),
+	)
+}
@@ -0,0 +1,50 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBPin(t *testing.T) {
+	original := dbPinPath
+	dbPinPath = filepath.Join(t.TempDir(), "grype", "pin.json")
+	t.Cleanup(func() { dbPinPath = original })
+
+	pin, err := readDBPin()
+	require.NoError(t, err)
+	assert.Nil(t, pin, "no pin should exist yet")
+
+	want := &DBPin{
+		Built:         time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC),
+		SchemaVersion: 5,
+		Checksum:      "sha256:abc123",
+		PinnedAt:      time.Date(2023, 10, 2, 9, 30, 0, 0, time.UTC),
+	}
+	require.NoError(t, writeDBPin(want))
+
+	got, err := readDBPin()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	assert.NoError(t, got.check("sha256:abc123"))
+	assert.Error(t, got.check("sha256:def456"))
+
+	require.NoError(t, UnpinDB())
+	pin, err = readDBPin()
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	// Unpinning when nothing is pinned is a no-op.
+	assert.NoError(t, UnpinDB())
+}
+
+func TestDBPin_nilAcceptsAnyDatabase(t *testing.T) {
+	var pin *DBPin
+	assert.NoError(t, pin.check("sha256:anything"))
+	assert.True(t, dbConfig(pin).ValidateAge)
+	assert.False(t, dbConfig(&DBPin{}).ValidateAge)
+}
@@ -0,0 +1,28 @@
+package scan
+
+import (
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
+)
+
+// EnrichWithExploitability sets the exploitability data for each finding's
+// vulnerability, looking up the vulnerability by its ID and its aliases.
+func EnrichWithExploitability(findings []*Finding, data *exploit.Data) {
+	for _, f := range findings {
+		ids := append([]string{f.Vulnerability.ID}, f.Vulnerability.Aliases...)
+		f.Vulnerability.Exploitability = data.Lookup(ids...)
+	}
+}
+
+// KnownExploitedFindings returns the findings whose vulnerabilities are listed
+// in the KEV catalog. Findings must first be enriched using
+// EnrichWithExploitability.
+func KnownExploitedFindings(findings []*Finding) []*Finding {
+	var kev []*Finding
+	for _, f := range findings {
+		if f.Vulnerability.Exploitability.KnownExploited() {
+			kev = append(kev, f)
+		}
+	}
+
+	return kev
+}
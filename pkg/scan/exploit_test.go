@@ -0,0 +1,43 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/exploit"
+)
+
+func TestEnrichWithExploitability(t *testing.T) {
+	data, err := exploit.Load("../vuln/exploit/testdata/epss_scores.csv", "../vuln/exploit/testdata/known_exploited_vulnerabilities.json")
+	require.NoError(t, err)
+
+	findings := []*Finding{
+		{
+			Vulnerability: Vulnerability{
+				ID:      "GHSA-jfh8-c2jp-5v3q",
+				Aliases: []string{"CVE-2021-44228"},
+			},
+		},
+		{
+			Vulnerability: Vulnerability{
+				ID: "CVE-2020-8927",
+			},
+		},
+		{
+			Vulnerability: Vulnerability{
+				ID: "CVE-2000-0001",
+			},
+		},
+	}
+
+	EnrichWithExploitability(findings, data)
+
+	assert.True(t, findings[0].Vulnerability.Exploitability.KnownExploited())
+	assert.Equal(t, 0.00125, findings[1].Vulnerability.Exploitability.EPSSProbability())
+	assert.Nil(t, findings[2].Vulnerability.Exploitability)
+
+	kev := KnownExploitedFindings(findings)
+	require.Len(t, kev, 1)
+	assert.Equal(t, "GHSA-jfh8-c2jp-5v3q", kev[0].Vulnerability.ID)
+}
@@ -3,7 +3,6 @@ package scan
 import (
 	"fmt"
 
-	"github.com/samber/lo"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
 )
@@ -15,75 +14,266 @@ const (
 
 var ValidAdvisoriesSets = []string{AdvisoriesSetResolved, AdvisoriesSetAll}
 
+// SuppressedFinding is a finding that was filtered out because of an advisory
+// for the target APK.
+type SuppressedFinding struct {
+	Finding
+
+	// AdvisoryID is the ID of the advisory that caused the finding to be
+	// filtered out.
+	AdvisoryID string
+
+	// EventType is the type of the latest event in the advisory.
+	EventType string
+
+	// FalsePositiveType is the type of false positive determination, if that's
+	// the latest event in the advisory.
+	FalsePositiveType string `json:",omitempty"`
+
+	// Note is the note of the latest event in the advisory, if it has one.
+	Note string `json:",omitempty"`
+
+	// Justification is a human-readable explanation of why the finding was
+	// filtered out, derived from the latest event in the advisory.
+	Justification string
+}
+
 // FilterWithAdvisories filters the findings in the result based on the advisories for the target APK.
+// For image scans, each finding is filtered based on the advisories for the APK
+// that owns the affected package.
 func FilterWithAdvisories(result *Result, advisoryCfgs *configs.Index[v2.Document], advisoryFilterSet string) ([]*Finding, error) {
-	if result == nil {
-		return nil, fmt.Errorf("result cannot be nil")
+	kept, _, err := partitionWithAdvisories(result, advisoryCfgs, advisoryFilterSet)
+	return kept, err
+}
+
+// SuppressWithAdvisories filters the findings in the result like
+// FilterWithAdvisories, but instead of discarding the findings that are filtered
+// out, it keeps them in the result's Suppressed findings, along with the
+// advisory data that justifies filtering out each one.
+func SuppressWithAdvisories(result *Result, advisoryCfgs *configs.Index[v2.Document], advisoryFilterSet string) error {
+	kept, suppressed, err := partitionWithAdvisories(result, advisoryCfgs, advisoryFilterSet)
+	if err != nil {
+		return err
 	}
 
-	if advisoryCfgs == nil {
-		return nil, fmt.Errorf("advisory configs cannot be nil")
+	result.Findings = kept
+	result.Suppressed = append(result.Suppressed, suppressed...)
+	return nil
+}
+
+func partitionWithAdvisories(result *Result, advisoryCfgs *configs.Index[v2.Document], advisoryFilterSet string) ([]*Finding, []SuppressedFinding, error) {
+	if result == nil {
+		return nil, nil, fmt.Errorf("result cannot be nil")
 	}
 
-	documents := advisoryCfgs.Select().WhereName(result.TargetAPK.Name).Configurations()
-	if len(documents) == 0 {
-		// No advisory configs for this package, so we know we wouldn't be able to filter anything.
-		return result.Findings, nil
+	if advisoryCfgs == nil {
+		return nil, nil, fmt.Errorf("advisory configs cannot be nil")
 	}
 
-	// We know there's an advisories document for this package, so we can get the advisories.
-	packageAdvisories := documents[0].Advisories
+	var filters func(adv v2.Advisory, version string) bool
 
 	switch advisoryFilterSet {
 	case AdvisoriesSetAll:
-		resultFindings := lo.Filter(result.Findings, func(finding *Finding, _ int) bool {
-			adv, ok := packageAdvisories.GetByVulnerability(finding.Vulnerability.ID)
-			// If the advisory contains any events, filter it out!
-			if ok && len(adv.Events) >= 1 {
-				return false
-			}
+		// If the advisory contains any events, filter it out!
+		filters = func(adv v2.Advisory, _ string) bool {
+			return len(adv.Events) >= 1
+		}
 
-			// Also check any listed aliases
-			for _, alias := range finding.Vulnerability.Aliases {
-				adv, ok := packageAdvisories.GetByVulnerability(alias)
-				if !ok {
-					continue
-				}
+	case AdvisoriesSetResolved:
+		filters = func(adv v2.Advisory, version string) bool {
+			return adv.ResolvedAtVersion(version)
+		}
 
-				if len(adv.Events) >= 1 {
-					return false
-				}
-			}
+	default:
+		return nil, nil, fmt.Errorf("unknown advisory filter set: %s", advisoryFilterSet)
+	}
+
+	// Findings are filtered using the advisories of the APK that owns the
+	// affected package. For an APK scan, that's always the target APK, but for an
+	// image scan, each finding can belong to a different APK.
+	advisoriesFor := newAdvisoryLookup(advisoryCfgs)
+
+	kept := make([]*Finding, 0, len(result.Findings))
+	var suppressed []SuppressedFinding
+
+	for _, finding := range result.Findings {
+		target := result.TargetAPK
+		if finding.APK != nil {
+			target = *finding.APK
+		}
+
+		packageAdvisories, ok := advisoriesFor(target)
+		if !ok {
+			// No advisories for this package, so we know we wouldn't be able to filter this finding.
+			kept = append(kept, finding)
+			continue
+		}
+
+		finding = withSeverityOverride(packageAdvisories, finding)
 
-			return true
+		adv, ok := findFilteringAdvisory(packageAdvisories, finding.Vulnerability, func(adv v2.Advisory) bool {
+			return filters(adv, target.Version)
 		})
+		if !ok {
+			kept = append(kept, finding)
+			continue
+		}
 
-		return resultFindings, nil
+		latest := adv.Latest()
+		fpType, note := eventDetails(latest)
+		suppressed = append(suppressed, SuppressedFinding{
+			Finding:           *finding,
+			AdvisoryID:        adv.ID,
+			EventType:         latest.Type,
+			FalsePositiveType: fpType,
+			Note:              note,
+			Justification:     justification(latest),
+		})
+	}
 
-	case AdvisoriesSetResolved:
-		resultFindings := lo.Filter(result.Findings, func(finding *Finding, _ int) bool {
-			adv, ok := packageAdvisories.GetByVulnerability(finding.Vulnerability.ID)
-			if ok && adv.ResolvedAtVersion(result.TargetAPK.Version) {
-				return false
-			}
+	return kept, suppressed, nil
+}
 
-			// Also check any listed aliases
-			for _, alias := range finding.Vulnerability.Aliases {
-				adv, ok := packageAdvisories.GetByVulnerability(alias)
-				if !ok {
-					continue
-				}
+// ApplySeverityOverrides replaces the severity of each finding in the result
+// with the distro's own severity rating, where the advisory for the finding's
+// vulnerability has one. Unlike filtering, this applies whenever advisories are
+// available, so that the rating is shown for the findings that are kept.
+func ApplySeverityOverrides(result *Result, advisoryCfgs *configs.Index[v2.Document]) error {
+	if result == nil {
+		return fmt.Errorf("result cannot be nil")
+	}
 
-				if adv.ResolvedAtVersion(result.TargetAPK.Version) {
-					return false
-				}
+	if advisoryCfgs == nil {
+		return fmt.Errorf("advisory configs cannot be nil")
+	}
+
+	advisoriesFor := newAdvisoryLookup(advisoryCfgs)
+	for i, finding := range result.Findings {
+		target := result.TargetAPK
+		if finding.APK != nil {
+			target = *finding.APK
+		}
+
+		if packageAdvisories, ok := advisoriesFor(target); ok {
+			result.Findings[i] = withSeverityOverride(packageAdvisories, finding)
+		}
+	}
+
+	return nil
+}
+
+// newAdvisoryLookup returns a function that returns the advisories for the
+// given APK, and whether there are any. Advisories are recorded for the origin
+// package, which covers all of its subpackages. Each origin's advisories are
+// looked up only once.
+func newAdvisoryLookup(advisoryCfgs *configs.Index[v2.Document]) func(TargetAPK) (v2.Advisories, bool) {
+	advisoriesByPackage := make(map[string]v2.Advisories)
+
+	return func(target TargetAPK) (v2.Advisories, bool) {
+		name := target.Origin()
+		if advs, ok := advisoriesByPackage[name]; ok {
+			return advs, advs != nil
+		}
+
+		var advs v2.Advisories
+		if documents := advisoryCfgs.Select().WhereName(name).Configurations(); len(documents) > 0 {
+			advs = documents[0].Advisories
+		}
+		advisoriesByPackage[name] = advs
+
+		return advs, advs != nil
+	}
+}
+
+// findFilteringAdvisory returns the first advisory for the vulnerability (or
+// any of its aliases) that the given filter says should filter out the finding.
+func findFilteringAdvisory(advisories v2.Advisories, v Vulnerability, filters func(v2.Advisory) bool) (v2.Advisory, bool) {
+	for _, id := range append([]string{v.ID}, v.Aliases...) {
+		adv, ok := advisories.GetByVulnerability(id)
+		if ok && filters(adv) {
+			return adv, true
+		}
+	}
+
+	return v2.Advisory{}, false
+}
+
+// withSeverityOverride returns the finding with its severity replaced by the
+// distro's own severity rating from the advisory for the vulnerability (or any
+// of its aliases), if the advisory has one. The given finding isn't modified.
+func withSeverityOverride(advisories v2.Advisories, finding *Finding) *Finding {
+	for _, id := range append([]string{finding.Vulnerability.ID}, finding.Vulnerability.Aliases...) {
+		adv, ok := advisories.GetByVulnerability(id)
+		if !ok {
+			continue
+		}
+
+		severity, ok := adv.SeverityOverride()
+		if !ok {
+			// An alias's advisory might have one.
+			continue
+		}
+
+		overridden := *finding
+		overridden.Vulnerability.Severity = string(severity)
+		return &overridden
+	}
+
+	return finding
+}
+
+// eventDetails returns the false positive type and the note recorded in the
+// event, where the event type has them.
+func eventDetails(event v2.Event) (fpType, note string) {
+	switch data := event.Data.(type) {
+	case v2.FalsePositiveDetermination:
+		return data.Type, data.Note
+	case v2.TruePositiveDetermination:
+		return "", data.Note
+	case v2.FixNotPlanned:
+		return "", data.Note
+	case v2.AnalysisNotPlanned:
+		return "", data.Note
+	}
+
+	return "", ""
+}
+
+func justification(event v2.Event) string {
+	switch event.Type {
+	case v2.EventTypeFalsePositiveDetermination:
+		if data, ok := event.Data.(v2.FalsePositiveDetermination); ok {
+			if data.Note != "" {
+				return fmt.Sprintf("false positive (%s): %s", data.Type, data.Note)
 			}
+			return fmt.Sprintf("false positive (%s)", data.Type)
+		}
+		return "false positive"
 
-			return true
-		})
+	case v2.EventTypeFixed:
+		if data, ok := event.Data.(v2.Fixed); ok && data.FixedVersion != "" {
+			return fmt.Sprintf("fixed in %s", data.FixedVersion)
+		}
+		return "fixed"
+
+	case v2.EventTypeFixNotPlanned:
+		if data, ok := event.Data.(v2.FixNotPlanned); ok && data.Note != "" {
+			return fmt.Sprintf("fix not planned: %s", data.Note)
+		}
+		return "fix not planned"
+
+	case v2.EventTypeAnalysisNotPlanned:
+		if data, ok := event.Data.(v2.AnalysisNotPlanned); ok && data.Note != "" {
+			return fmt.Sprintf("analysis not planned: %s", data.Note)
+		}
+		return "analysis not planned"
+
+	case v2.EventTypeTruePositiveDetermination:
+		return "true positive, fix in progress"
 
-		return resultFindings, nil
+	case v2.EventTypeDetection:
+		return "under investigation"
 	}
 
-	return nil, fmt.Errorf("unknown advisory filter set: %s", advisoryFilterSet)
+	return event.Type
 }
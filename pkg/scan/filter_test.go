@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
@@ -220,6 +221,90 @@ func TestFilterWithAdvisories(t *testing.T) {
 			},
 			errAssertion: assert.NoError,
 		},
+		{
+			name: "subpackage filtered using origin's advisories",
+			result: &Result{
+				TargetAPK: TargetAPK{
+					Name:              "ko-docs",
+					Version:           "0.13.0-r3",
+					OriginPackageName: "ko",
+				},
+				Findings: []*Finding{
+					{
+						Vulnerability: Vulnerability{
+							ID: "CVE-2023-1234",
+						},
+					},
+					{
+						Vulnerability: Vulnerability{
+							ID: "GHSA-2h5h-59f5-c5x9",
+						},
+					},
+				},
+			},
+			advisoryIndexGetter: getAdvisoriesIndex,
+			advisoryFilterSet:   "resolved",
+			expectedFindings: []*Finding{
+				{
+					Vulnerability: Vulnerability{
+						ID: "CVE-2023-1234",
+					},
+				},
+			},
+			errAssertion: assert.NoError,
+		},
+		{
+			name: "image findings filtered by owning apk",
+			result: &Result{
+				TargetImage: &TargetImage{
+					Name: "example.com/image:latest",
+				},
+				Findings: []*Finding{
+					{
+						Vulnerability: Vulnerability{
+							ID: "GHSA-2h5h-59f5-c5x9",
+						},
+						APK: &TargetAPK{
+							Name:    "ko",
+							Version: "0.13.0-r3",
+						},
+					},
+					{
+						Vulnerability: Vulnerability{
+							ID: "GHSA-2h5h-59f5-c5x9",
+						},
+						APK: &TargetAPK{
+							Name:    "ko",
+							Version: "0.13.0-r2",
+						},
+					},
+					{
+						Vulnerability: Vulnerability{
+							ID: "GHSA-2h5h-59f5-c5x9",
+						},
+					},
+				},
+			},
+			advisoryIndexGetter: getAdvisoriesIndex,
+			advisoryFilterSet:   "resolved",
+			expectedFindings: []*Finding{
+				{
+					Vulnerability: Vulnerability{
+						ID: "GHSA-2h5h-59f5-c5x9",
+					},
+					APK: &TargetAPK{
+						Name:    "ko",
+						Version: "0.13.0-r2",
+					},
+				},
+				{
+					Vulnerability: Vulnerability{
+						ID: "GHSA-2h5h-59f5-c5x9",
+					},
+				},
+			},
+			errAssertion: assert.NoError,
+		},
 	}
 
 	for _, tt := range cases {
@@ -237,6 +322,92 @@ func TestFilterWithAdvisories(t *testing.T) {
 	}
 }
 
+func TestSuppressWithAdvisories(t *testing.T) {
+	result := &Result{
+		TargetAPK: TargetAPK{
+			Name:    "ko",
+			Version: "0.13.0-r3",
+		},
+		Findings: []*Finding{
+			{Vulnerability: Vulnerability{ID: "CVE-1999-11111"}},
+			{Vulnerability: Vulnerability{ID: "GHSA-2h5h-59f5-c5x9"}},
+			{Vulnerability: Vulnerability{ID: "CVE-2023-12345"}},
+		},
+	}
+
+	err := SuppressWithAdvisories(result, getAdvisoriesIndex(t), AdvisoriesSetResolved)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Finding{
+		{Vulnerability: Vulnerability{ID: "CVE-1999-11111"}},
+		{Vulnerability: Vulnerability{ID: "CVE-2023-12345"}},
+	}, result.Findings)
+	assert.Equal(t, []SuppressedFinding{
+		{
+			Finding:       Finding{Vulnerability: Vulnerability{ID: "GHSA-2h5h-59f5-c5x9"}},
+			AdvisoryID:    "GHSA-2h5h-59f5-c5x9",
+			EventType:     v2.EventTypeFixed,
+			Justification: "fixed in 0.13.0-r3",
+		},
+	}, result.Suppressed)
+}
+
+func TestFilterWithAdvisories_SeverityOverride(t *testing.T) {
+	original := &Finding{Vulnerability: Vulnerability{ID: "GHSA-wxyz-wxyz-wxyz", Aliases: []string{"CVE-2023-33333"}, Severity: "Critical"}}
+	result := &Result{
+		TargetAPK: TargetAPK{
+			Name:    "ko",
+			Version: "0.13.0-r3",
+		},
+		Findings: []*Finding{
+			original,
+			{Vulnerability: Vulnerability{ID: "CVE-2023-12345", Severity: "High"}},
+			// The advisory for the finding's own ID has no override, but the
+			// advisory for its alias does.
+			{Vulnerability: Vulnerability{ID: "GHSA-aaaa-bbbb-cccc", Aliases: []string{"CVE-2023-33333"}, Severity: "High"}},
+		},
+	}
+
+	index, err := v2.NewIndex(rwos.DirFS(path.Join("testdata", "severity-override")))
+	require.NoError(t, err)
+
+	findings, err := FilterWithAdvisories(result, index, AdvisoriesSetResolved)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Finding{
+		{Vulnerability: Vulnerability{ID: "GHSA-wxyz-wxyz-wxyz", Aliases: []string{"CVE-2023-33333"}, Severity: "Low"}},
+		{Vulnerability: Vulnerability{ID: "CVE-2023-12345", Severity: "High"}},
+		{Vulnerability: Vulnerability{ID: "GHSA-aaaa-bbbb-cccc", Aliases: []string{"CVE-2023-33333"}, Severity: "Low"}},
+	}, findings)
+
+	// The finding in the result isn't modified.
+	assert.Equal(t, "Critical", original.Vulnerability.Severity)
+}
+
+func TestApplySeverityOverrides(t *testing.T) {
+	result := &Result{
+		TargetAPK: TargetAPK{
+			Name:    "ko",
+			Version: "0.13.0-r3",
+		},
+		Findings: []*Finding{
+			{Vulnerability: Vulnerability{ID: "CVE-2023-33333", Severity: "Critical"}},
+			{Vulnerability: Vulnerability{ID: "CVE-2023-12345", Severity: "High"}},
+		},
+	}
+
+	index, err := v2.NewIndex(rwos.DirFS(path.Join("testdata", "severity-override")))
+	require.NoError(t, err)
+
+	require.NoError(t, ApplySeverityOverrides(result, index))
+
+	// Nothing is filtered out, but the true positive has the distro's rating.
+	assert.Equal(t, []*Finding{
+		{Vulnerability: Vulnerability{ID: "CVE-2023-33333", Severity: "Low"}},
+		{Vulnerability: Vulnerability{ID: "CVE-2023-12345", Severity: "High"}},
+	}, result.Findings)
+}
+
 func getAdvisoriesIndex(t *testing.T) *configs.Index[v2.Document] {
 	t.Helper()
 
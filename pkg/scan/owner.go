@@ -0,0 +1,64 @@
+package scan
+
+import (
+	"path"
+	"strings"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// apkOwnership maps the files installed in an image to the APKs that own them,
+// per the image's installed APK database.
+type apkOwnership struct {
+	apksByID   map[string]TargetAPK
+	apksByPath map[string]TargetAPK
+}
+
+func newAPKOwnership(pkgs []pkg.Package) apkOwnership {
+	o := apkOwnership{
+		apksByID:   make(map[string]TargetAPK),
+		apksByPath: make(map[string]TargetAPK),
+	}
+
+	for i := range pkgs {
+		p := pkgs[i]
+		if p.Type != pkg.ApkPkg {
+			continue
+		}
+
+		apk := newTargetAPKFromPackage(p)
+		o.apksByID[string(p.ID())] = apk
+
+		metadata, ok := p.Metadata.(pkg.ApkMetadata)
+		if !ok {
+			continue
+		}
+
+		for _, f := range metadata.OwnedFiles() {
+			o.apksByPath[normalizePath(f)] = apk
+		}
+	}
+
+	return o
+}
+
+// ownerOf returns the APK that owns the given package, or nil if it can't be
+// determined. An APK package is considered to own itself.
+func (o apkOwnership) ownerOf(p grypePkg.Package) *TargetAPK {
+	if apk, ok := o.apksByID[string(p.ID)]; ok {
+		return &apk
+	}
+
+	for _, l := range p.Locations.ToSlice() {
+		if apk, ok := o.apksByPath[normalizePath(l.RealPath)]; ok {
+			return &apk
+		}
+	}
+
+	return nil
+}
+
+func normalizePath(p string) string {
+	return path.Clean("/" + strings.TrimPrefix(p, "/"))
+}
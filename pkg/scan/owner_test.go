@@ -0,0 +1,93 @@
+package scan
+
+import (
+	"testing"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPKOwnership(t *testing.T) {
+	ko := pkg.Package{
+		Name:         "ko",
+		Version:      "0.13.0-r2",
+		Type:         pkg.ApkPkg,
+		MetadataType: pkg.ApkMetadataType,
+		Metadata: pkg.ApkMetadata{
+			Package: "ko",
+			Files: []pkg.ApkFileRecord{
+				{Path: "/usr/bin"},
+				{Path: "/usr/bin/ko"},
+			},
+		},
+	}
+	ko.SetID()
+
+	koDocs := pkg.Package{
+		Name:         "ko-docs",
+		Version:      "0.13.0-r2",
+		Type:         pkg.ApkPkg,
+		MetadataType: pkg.ApkMetadataType,
+		Metadata: pkg.ApkMetadata{
+			Package:       "ko-docs",
+			OriginPackage: "ko",
+			Files: []pkg.ApkFileRecord{
+				{Path: "/usr/share/doc/ko/README.md"},
+			},
+		},
+	}
+	koDocs.SetID()
+
+	goModule := pkg.Package{
+		Name:    "github.com/sirupsen/logrus",
+		Version: "v1.9.0",
+		Type:    pkg.GoModulePkg,
+	}
+	goModule.SetID()
+
+	owners := newAPKOwnership([]pkg.Package{ko, koDocs, goModule})
+
+	cases := []struct {
+		name     string
+		pkg      grypePkg.Package
+		expected *TargetAPK
+	}{
+		{
+			name:     "apk owns itself",
+			pkg:      grypePkg.Package{ID: grypePkg.ID(ko.ID())},
+			expected: &TargetAPK{Name: "ko", Version: "0.13.0-r2"},
+		},
+		{
+			name: "package in a file owned by an apk",
+			pkg: grypePkg.Package{
+				ID:        grypePkg.ID(goModule.ID()),
+				Locations: file.NewLocationSet(file.NewLocation("/usr/bin/ko")),
+			},
+			expected: &TargetAPK{Name: "ko", Version: "0.13.0-r2"},
+		},
+		{
+			name: "package in a file owned by a subpackage",
+			pkg: grypePkg.Package{
+				ID:        grypePkg.ID(goModule.ID()),
+				Locations: file.NewLocationSet(file.NewLocation("/usr/share/doc/ko/README.md")),
+			},
+			expected: &TargetAPK{Name: "ko-docs", Version: "0.13.0-r2", OriginPackageName: "ko"},
+		},
+		{
+			name: "package in a file not owned by any apk",
+			pkg: grypePkg.Package{
+				ID:        grypePkg.ID(goModule.ID()),
+				Locations: file.NewLocationSet(file.NewLocation("/app/server")),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, owners.ownerOf(tt.pkg))
+		})
+	}
+}
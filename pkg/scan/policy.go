@@ -0,0 +1,279 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"gopkg.in/yaml.v3"
+)
+
+// policyDateLayout is the layout of the expiry dates of ignore rules.
+const policyDateLayout = "2006-01-02"
+
+// Policy describes which scan findings are acceptable. Policies are meant for
+// risk decisions that are local to a team or project, and which don't belong in
+// the distro's public advisory data. A policy is applied after findings have
+// been filtered using advisories.
+type Policy struct {
+	// FailOn is the lowest vulnerability severity that violates the policy (e.g.
+	// "High"). If empty, every finding violates the policy.
+	FailOn vuln.Severity `yaml:"fail-on,omitempty"`
+
+	// Ignore lists the rules for findings that are temporarily accepted.
+	Ignore []IgnoreRule `yaml:"ignore,omitempty"`
+}
+
+// IgnoreRule matches findings that are temporarily accepted. A finding matches
+// the rule if it matches every criterion that's set on the rule, and at least
+// one criterion must be set.
+type IgnoreRule struct {
+	// Vulnerability matches the vulnerability ID or any of its aliases.
+	Vulnerability string `yaml:"vulnerability,omitempty"`
+
+	// Package matches the name of the affected package.
+	Package string `yaml:"package,omitempty"`
+
+	// Location is a glob pattern (as used by path.Match) that matches any of the
+	// locations of the affected package, e.g. "/usr/bin/*".
+	Location string `yaml:"location,omitempty"`
+
+	// Type matches the type of the affected package, e.g. "go-module".
+	Type string `yaml:"type,omitempty"`
+
+	// Justification explains why the matching findings are acceptable.
+	Justification string `yaml:"justification"`
+
+	// Expires is the last day (as YYYY-MM-DD, in UTC) on which the rule applies.
+	// After that, the findings that match the rule violate the policy again.
+	Expires string `yaml:"expires"`
+}
+
+// LoadPolicy reads and validates the policy YAML file at the given path.
+func LoadPolicy(policyPath string) (*Policy, error) {
+	f, err := os.Open(policyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policy: %w", err)
+	}
+	defer f.Close()
+
+	policy, err := decodePolicy(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode policy %q: %w", policyPath, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %q: %w", policyPath, err)
+	}
+
+	return policy, nil
+}
+
+func decodePolicy(r io.Reader) (*Policy, error) {
+	policy := &Policy{}
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	err := decoder.Decode(policy)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Validate returns an error if the policy is invalid.
+func (p Policy) Validate() error {
+	var errs []error
+
+	if p.FailOn != "" && !slices.Contains(vuln.Severities, p.FailOn) {
+		errs = append(errs, fmt.Errorf("fail-on: invalid severity %q, must be one of [%s]", p.FailOn, vuln.SeveritiesString()))
+	}
+
+	for i, rule := range p.Ignore {
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("ignore[%d]: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate returns an error if the ignore rule is invalid.
+func (r IgnoreRule) Validate() error {
+	var errs []error
+
+	if r.Vulnerability == "" && r.Package == "" && r.Location == "" && r.Type == "" {
+		errs = append(errs, errors.New("at least one of vulnerability, package, location, or type must be set"))
+	}
+
+	if r.Location != "" {
+		if _, err := path.Match(r.Location, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid location pattern %q: %w", r.Location, err))
+		}
+	}
+
+	if strings.TrimSpace(r.Justification) == "" {
+		errs = append(errs, errors.New("justification must not be empty"))
+	}
+
+	if _, err := r.expiry(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r IgnoreRule) expiry() (time.Time, error) {
+	if r.Expires == "" {
+		return time.Time{}, errors.New("expires must not be empty")
+	}
+
+	day, err := time.Parse(policyDateLayout, r.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expires date %q, must be formatted as YYYY-MM-DD", r.Expires)
+	}
+
+	// The rule still applies on the day it expires.
+	return day.AddDate(0, 0, 1), nil
+}
+
+// Expired returns true if the rule no longer applies at the given time.
+func (r IgnoreRule) Expired(now time.Time) bool {
+	expiry, err := r.expiry()
+	if err != nil {
+		return true
+	}
+
+	return !now.Before(expiry)
+}
+
+// Matches returns true if the finding matches every criterion set on the rule.
+// It doesn't consider whether the rule has expired.
+func (r IgnoreRule) Matches(f Finding) bool {
+	if r.Vulnerability != "" && r.Vulnerability != f.Vulnerability.ID && !slices.Contains(f.Vulnerability.Aliases, r.Vulnerability) {
+		return false
+	}
+
+	if r.Package != "" && r.Package != f.Package.Name {
+		return false
+	}
+
+	if r.Type != "" && r.Type != f.Package.Type {
+		return false
+	}
+
+	if r.Location != "" {
+		matched := false
+		for _, loc := range strings.Split(f.Package.Location, ", ") {
+			if ok, _ := path.Match(r.Location, loc); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the findings that aren't ignored by any of the policy's
+// unexpired ignore rules.
+func (p Policy) Filter(findings []*Finding, now time.Time) []*Finding {
+	kept := make([]*Finding, 0, len(findings))
+	for _, f := range findings {
+		if _, ok := p.activeIgnoreRule(*f, now); ok {
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	return kept
+}
+
+// PolicyViolation is a finding that violates a policy.
+type PolicyViolation struct {
+	Finding *Finding
+
+	// Reason explains how the finding violates the policy.
+	Reason string
+}
+
+// Violations returns the findings that violate the policy at the given time.
+// A finding violates the policy if its severity is at or above the policy's
+// fail-on severity, or if it's matched by an ignore rule that has expired.
+// Findings ignored by an unexpired ignore rule never violate the policy.
+func (p Policy) Violations(findings []*Finding, now time.Time) []PolicyViolation {
+	var violations []PolicyViolation
+
+	for _, f := range findings {
+		if _, ok := p.activeIgnoreRule(*f, now); ok {
+			continue
+		}
+
+		if rule, ok := p.expiredIgnoreRule(*f, now); ok {
+			violations = append(violations, PolicyViolation{
+				Finding: f,
+				Reason:  fmt.Sprintf("ignore rule expired on %s (%s)", rule.Expires, rule.Justification),
+			})
+			continue
+		}
+
+		if p.meetsFailOn(f.Vulnerability.Severity) {
+			reason := "any finding fails the policy"
+			if p.FailOn != "" {
+				reason = fmt.Sprintf("severity is at or above %s", p.FailOn)
+			}
+
+			violations = append(violations, PolicyViolation{
+				Finding: f,
+				Reason:  reason,
+			})
+		}
+	}
+
+	return violations
+}
+
+func (p Policy) activeIgnoreRule(f Finding, now time.Time) (IgnoreRule, bool) {
+	for _, rule := range p.Ignore {
+		if !rule.Expired(now) && rule.Matches(f) {
+			return rule, true
+		}
+	}
+
+	return IgnoreRule{}, false
+}
+
+func (p Policy) expiredIgnoreRule(f Finding, now time.Time) (IgnoreRule, bool) {
+	for _, rule := range p.Ignore {
+		if rule.Expired(now) && rule.Matches(f) {
+			return rule, true
+		}
+	}
+
+	return IgnoreRule{}, false
+}
+
+// meetsFailOn returns true if the given severity (as reported in a finding,
+// e.g. "High") is at or above the policy's fail-on severity. Unrecognized
+// severities (e.g. "Unknown") only meet an empty fail-on severity.
+func (p Policy) meetsFailOn(severity string) bool {
+	if p.FailOn == "" {
+		return true
+	}
+
+	rank := slices.Index(vuln.Severities, vuln.Severity(severity))
+	if rank < 0 {
+		return false
+	}
+
+	return rank >= slices.Index(vuln.Severities, p.FailOn)
+}
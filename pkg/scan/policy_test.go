@@ -0,0 +1,77 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		policy, err := LoadPolicy(filepath.Join("testdata", "policy", "valid.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, vuln.SeverityHigh, policy.FailOn)
+		assert.Len(t, policy.Ignore, 3)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := LoadPolicy(filepath.Join("testdata", "policy", "invalid.yaml"))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `invalid severity "severe"`)
+		assert.ErrorContains(t, err, "ignore[0]: at least one of")
+		assert.ErrorContains(t, err, "ignore[1]: justification must not be empty")
+		assert.ErrorContains(t, err, `invalid expires date "31/12/2023"`)
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	policy, err := LoadPolicy(filepath.Join("testdata", "policy", "valid.yaml"))
+	require.NoError(t, err)
+
+	// The second ignore rule has expired, but the others still apply.
+	now := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	ignoredByVulnerability := &Finding{
+		Package:       Package{Name: "ko", Type: "apk"},
+		Vulnerability: Vulnerability{ID: "GHSA-xxxx-xxxx-xxxx", Aliases: []string{"CVE-2023-0001"}, Severity: "Critical"},
+	}
+	ignoredByType := &Finding{
+		Package:       Package{Name: "log4j-core", Type: "java-archive"},
+		Vulnerability: Vulnerability{ID: "CVE-2023-0002", Severity: "Critical"},
+	}
+	expiredIgnore := &Finding{
+		Package:       Package{Name: "github.com/sirupsen/logrus", Type: "go-module", Location: "/usr/bin/ko"},
+		Vulnerability: Vulnerability{ID: "CVE-2023-0003", Severity: "Low"},
+	}
+	belowThreshold := &Finding{
+		Package:       Package{Name: "github.com/sirupsen/logrus", Type: "go-module", Location: "/usr/lib/ko"},
+		Vulnerability: Vulnerability{ID: "CVE-2023-0004", Severity: "Medium"},
+	}
+	atThreshold := &Finding{
+		Package:       Package{Name: "openssl", Type: "apk"},
+		Vulnerability: Vulnerability{ID: "CVE-2023-0005", Severity: "High"},
+	}
+
+	findings := []*Finding{ignoredByVulnerability, ignoredByType, expiredIgnore, belowThreshold, atThreshold}
+
+	assert.Equal(t, []*Finding{expiredIgnore, belowThreshold, atThreshold}, policy.Filter(findings, now))
+
+	assert.Equal(t, []PolicyViolation{
+		{Finding: expiredIgnore, Reason: "ignore rule expired on 2023-06-30 (Awaiting an upstream release.)"},
+		{Finding: atThreshold, Reason: "severity is at or above High"},
+	}, policy.Violations(findings, now))
+
+	t.Run("ignore rule applies on its expiry date", func(t *testing.T) {
+		lastDay := time.Date(2023, 6, 30, 23, 59, 0, 0, time.UTC)
+		assert.Equal(t, []*Finding{belowThreshold, atThreshold}, policy.Filter(findings, lastDay))
+	})
+
+	t.Run("empty fail-on fails on any finding", func(t *testing.T) {
+		p := Policy{}
+		assert.Len(t, p.Violations(findings, now), len(findings))
+	})
+}
@@ -0,0 +1,216 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	version "github.com/knqyf263/go-apk-version"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	v2 "github.com/wolfi-dev/wolfictl/pkg/configs/advisory/v2"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+const apkindexFilename = "APKINDEX.tar.gz"
+
+// RepoAPK is an APK file in a package repository.
+type RepoAPK struct {
+	Name    string
+	Version string
+	Path    string
+}
+
+// LatestRepoAPKs returns the latest version of each package in the repository
+// at the given path, sorted by package name. The path can be a directory of APK
+// files or an APKINDEX archive.
+//
+// When an APKINDEX is available (either given directly, or found in the
+// directory), it determines the set of packages, and each APK file is expected
+// to be alongside the APKINDEX, named "<name>-<version>.apk". Otherwise, the
+// package name and version are parsed from the names of the APK files in the
+// directory.
+func LatestRepoAPKs(p string) ([]RepoAPK, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return latestAPKsFromIndex(p)
+	}
+
+	if _, err := os.Stat(filepath.Join(p, apkindexFilename)); err == nil {
+		return latestAPKsFromIndex(filepath.Join(p, apkindexFilename))
+	}
+
+	return latestAPKsFromDirectory(p)
+}
+
+func latestAPKsFromIndex(indexPath string) ([]RepoAPK, error) {
+	f, err := os.Open(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", indexPath, err)
+	}
+	defer f.Close()
+
+	index, err := repository.IndexFromArchive(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing APKINDEX %q: %w", indexPath, err)
+	}
+
+	dir := filepath.Dir(indexPath)
+	var apks []RepoAPK
+	for _, pkg := range index.Packages {
+		apks = append(apks, RepoAPK{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Path:    filepath.Join(dir, pkg.Filename()),
+		})
+	}
+
+	latest := latestByName(apks)
+	for _, apk := range latest {
+		if _, err := os.Stat(apk.Path); err != nil {
+			return nil, fmt.Errorf("APK for %s-%s listed in %q not found: %w", apk.Name, apk.Version, indexPath, err)
+		}
+	}
+
+	return latest, nil
+}
+
+func latestAPKsFromDirectory(dir string) ([]RepoAPK, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.apk"))
+	if err != nil {
+		return nil, err
+	}
+
+	var apks []RepoAPK
+	for _, p := range paths {
+		name, ver, err := parseAPKFilename(filepath.Base(p))
+		if err != nil {
+			return nil, err
+		}
+
+		apks = append(apks, RepoAPK{
+			Name:    name,
+			Version: ver,
+			Path:    p,
+		})
+	}
+
+	return latestByName(apks), nil
+}
+
+// parseAPKFilename parses the package name and version from an APK filename of
+// the form "<name>-<version>-r<epoch>.apk".
+func parseAPKFilename(filename string) (name, ver string, err error) {
+	parts := strings.Split(strings.TrimSuffix(filename, ".apk"), "-")
+	if len(parts) < 3 || !strings.HasPrefix(parts[len(parts)-1], "r") {
+		return "", "", fmt.Errorf("unable to parse package name and version from APK filename %q", filename)
+	}
+
+	name = strings.Join(parts[:len(parts)-2], "-")
+	ver = strings.Join(parts[len(parts)-2:], "-")
+	return name, ver, nil
+}
+
+func latestByName(apks []RepoAPK) []RepoAPK {
+	latest := make(map[string]RepoAPK)
+	for _, apk := range apks {
+		existing, ok := latest[apk.Name]
+		if !ok || apkVersionLess(existing.Version, apk.Version) {
+			latest[apk.Name] = apk
+		}
+	}
+
+	result := make([]RepoAPK, 0, len(latest))
+	for _, apk := range latest {
+		result = append(result, apk)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func apkVersionLess(a, b string) bool {
+	va, err := version.NewVersion(a)
+	if err != nil {
+		return a < b
+	}
+	vb, err := version.NewVersion(b)
+	if err != nil {
+		return a < b
+	}
+
+	return va.LessThan(vb)
+}
+
+// RepoReport aggregates the results of scanning many APKs, such as every
+// latest APK in a repository.
+type RepoReport struct {
+	// Results holds the scan result for each APK.
+	Results []*Result
+
+	// TotalsBySeverity counts the findings across all APKs by vulnerability
+	// severity.
+	TotalsBySeverity map[string]int
+
+	// UnresolvedAdvisories lists the IDs of the advisories that aren't resolved
+	// at the scanned version, for each package that has any.
+	UnresolvedAdvisories map[string][]string `json:",omitempty"`
+
+	// Comparison classifies the findings relative to a baseline scan, if one was
+	// given.
+	Comparison *Comparison `json:",omitempty"`
+}
+
+// NewRepoReport aggregates the given scan results. If advisoryCfgs is non-nil,
+// the report also lists the packages with unresolved advisories.
+func NewRepoReport(results []*Result, advisoryCfgs *configs.Index[v2.Document]) *RepoReport {
+	report := &RepoReport{
+		Results:          results,
+		TotalsBySeverity: make(map[string]int),
+	}
+
+	for _, result := range results {
+		for _, f := range result.Findings {
+			report.TotalsBySeverity[f.Vulnerability.Severity]++
+		}
+
+		if advisoryCfgs == nil {
+			continue
+		}
+
+		documents := advisoryCfgs.Select().WhereName(result.TargetAPK.Origin()).Configurations()
+		if len(documents) == 0 {
+			continue
+		}
+
+		for _, adv := range documents[0].Advisories {
+			if len(adv.Events) == 0 || adv.ResolvedAtVersion(result.TargetAPK.Version) {
+				continue
+			}
+
+			if report.UnresolvedAdvisories == nil {
+				report.UnresolvedAdvisories = make(map[string][]string)
+			}
+			report.UnresolvedAdvisories[result.TargetAPK.Name] = append(report.UnresolvedAdvisories[result.TargetAPK.Name], adv.ID)
+		}
+	}
+
+	return report
+}
+
+// TotalFindings returns the number of findings across all APKs.
+func (r RepoReport) TotalFindings() int {
+	total := 0
+	for _, count := range r.TotalsBySeverity {
+		total += count
+	}
+
+	return total
+}
@@ -0,0 +1,137 @@
+package scan
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPKFilename(t *testing.T) {
+	cases := []struct {
+		filename        string
+		expectedName    string
+		expectedVersion string
+		expectErr       bool
+	}{
+		{filename: "ko-0.13.0-r3.apk", expectedName: "ko", expectedVersion: "0.13.0-r3"},
+		{filename: "py3-setuptools-68.2.2-r0.apk", expectedName: "py3-setuptools", expectedVersion: "68.2.2-r0"},
+		{filename: "ko.apk", expectErr: true},
+		{filename: "ko-0.13.0.apk", expectErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.filename, func(t *testing.T) {
+			name, ver, err := parseAPKFilename(tt.filename)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, name)
+			assert.Equal(t, tt.expectedVersion, ver)
+		})
+	}
+}
+
+func TestLatestRepoAPKs(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"ko-0.13.0-r9.apk",
+		"ko-0.13.0-r10.apk",
+		"ko-0.12.0-r20.apk",
+		"brotli-1.0.9-r3.apk",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0o600))
+	}
+
+	expected := []RepoAPK{
+		{Name: "brotli", Version: "1.0.9-r3", Path: filepath.Join(dir, "brotli-1.0.9-r3.apk")},
+		{Name: "ko", Version: "0.13.0-r10", Path: filepath.Join(dir, "ko-0.13.0-r10.apk")},
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		apks, err := LatestRepoAPKs(dir)
+		require.NoError(t, err)
+		assert.Equal(t, expected, apks)
+	})
+
+	t.Run("APKINDEX", func(t *testing.T) {
+		indexPath := filepath.Join(dir, apkindexFilename)
+		writeTestAPKINDEX(t, indexPath, `C:Q1Deb0jNytkrjPW4N/eKLZ43BwOlw=
+P:ko
+V:0.13.0-r10
+A:x86_64
+
+C:Q1Deb0jNytkrjPW4N/eKLZ43BwOlx=
+P:ko
+V:0.13.0-r9
+A:x86_64
+
+C:Q1Deb0jNytkrjPW4N/eKLZ43BwOly=
+P:brotli
+V:1.0.9-r3
+A:x86_64
+
+`)
+
+		apks, err := LatestRepoAPKs(indexPath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, apks)
+
+		// The directory's APKINDEX is preferred over its APK filenames.
+		apks, err = LatestRepoAPKs(dir)
+		require.NoError(t, err)
+		assert.Equal(t, expected, apks)
+	})
+}
+
+func TestNewRepoReport(t *testing.T) {
+	results := []*Result{
+		{
+			TargetAPK: TargetAPK{Name: "ko", Version: "0.13.0-r3"},
+			Findings: []*Finding{
+				{Vulnerability: Vulnerability{ID: "CVE-2023-1", Severity: "High"}},
+				{Vulnerability: Vulnerability{ID: "CVE-2023-2", Severity: "High"}},
+				{Vulnerability: Vulnerability{ID: "CVE-2023-3", Severity: "Low"}},
+			},
+		},
+		{
+			TargetAPK: TargetAPK{Name: "brotli", Version: "1.0.9-r3"},
+			Findings: []*Finding{
+				{Vulnerability: Vulnerability{ID: "CVE-2023-4", Severity: "Critical"}},
+			},
+		},
+	}
+
+	report := NewRepoReport(results, getAdvisoriesIndex(t))
+
+	assert.Equal(t, map[string]int{"Critical": 1, "High": 2, "Low": 1}, report.TotalsBySeverity)
+	assert.Equal(t, 4, report.TotalFindings())
+	assert.Equal(t, map[string][]string{"ko": {"CVE-1999-11111"}}, report.UnresolvedAdvisories)
+}
+
+func writeTestAPKINDEX(t *testing.T, p, content string) {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     "APKINDEX",
+		Mode:     0o644,
+		Size:     int64(len(content)),
+		Typeflag: tar.TypeReg,
+	}))
+	_, err := tw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	require.NoError(t, os.WriteFile(p, buf.Bytes(), 0o600))
+}
@@ -0,0 +1,241 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	sarifToolName           = "wolfictl"
+	sarifToolInformationURI = "https://github.com/wolfi-dev/wolfictl"
+)
+
+// SARIF levels, as defined in the SARIF 2.1.0 specification.
+const (
+	SARIFLevelError   = "error"
+	SARIFLevelWarning = "warning"
+	SARIFLevelNote    = "note"
+)
+
+// SARIFLog is the top-level object of a SARIF 2.1.0 document. Only the subset
+// of the specification needed to describe scan results is modeled here.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID               string           `json:"id"`
+	ShortDescription SARIFMessage     `json:"shortDescription"`
+	HelpURI          string           `json:"helpUri,omitempty"`
+	Properties       map[string]any   `json:"properties,omitempty"`
+	DefaultConfig    *SARIFRuleConfig `json:"defaultConfiguration,omitempty"`
+}
+
+type SARIFRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      SARIFMessage       `json:"message"`
+	Locations    []SARIFLocation    `json:"locations,omitempty"`
+	Suppressions []SARIFSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]any     `json:"properties,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SARIFSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// SARIFInput is the scan data for a single scanned APK that should be
+// represented in a SARIF log.
+type SARIFInput struct {
+	Result *Result
+
+	// Suppressed is the set of findings filtered out by advisories. If
+	// non-empty, these are included in the log as suppressed results.
+	Suppressed []SuppressedFinding
+}
+
+// ToSARIF converts the given scan results to a SARIF log with a single run.
+// Each vulnerability becomes a rule, and each finding becomes a result for that
+// rule, located at the finding's package location.
+func ToSARIF(inputs []SARIFInput) *SARIFLog {
+	rulesByID := make(map[string]SARIFRule)
+	results := []SARIFResult{}
+
+	for _, input := range inputs {
+		if input.Result == nil {
+			continue
+		}
+
+		for _, f := range input.Result.Findings {
+			rulesByID[f.Vulnerability.ID] = sarifRule(f.Vulnerability)
+			results = append(results, sarifResult(input.Result.TargetAPK, *f))
+		}
+
+		for _, s := range input.Suppressed {
+			rulesByID[s.Vulnerability.ID] = sarifRule(s.Vulnerability)
+
+			r := sarifResult(input.Result.TargetAPK, s.Finding)
+			r.Suppressions = []SARIFSuppression{
+				{
+					Kind:          "external",
+					Status:        "accepted",
+					Justification: fmt.Sprintf("%s: %s", s.AdvisoryID, s.Justification),
+				},
+			}
+			results = append(results, r)
+		}
+	}
+
+	ruleIDs := make([]string, 0, len(rulesByID))
+	for id := range rulesByID {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	rules := make([]SARIFRule, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		rules = append(rules, rulesByID[id])
+	}
+
+	return &SARIFLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{
+					Driver: SARIFDriver{
+						Name:           sarifToolName,
+						InformationURI: sarifToolInformationURI,
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+// SARIFLevel maps a vulnerability severity to a SARIF result level.
+func SARIFLevel(severity string) string {
+	switch severity {
+	case "Critical", "High":
+		return SARIFLevelError
+	case "Medium":
+		return SARIFLevelWarning
+	case "Low", "Negligible":
+		return SARIFLevelNote
+	}
+
+	return SARIFLevelWarning
+}
+
+func sarifRule(v Vulnerability) SARIFRule {
+	description := v.ID
+	if len(v.Aliases) > 0 {
+		description = fmt.Sprintf("%s (%s)", v.ID, strings.Join(v.Aliases, ", "))
+	}
+
+	properties := map[string]any{
+		"tags": []string{"security", "vulnerability"},
+	}
+	if score, ok := v.HighestCVSSBaseScore(); ok {
+		// GitHub code scanning uses this property to rank security alerts.
+		properties["security-severity"] = fmt.Sprintf("%.1f", score)
+	}
+
+	return SARIFRule{
+		ID:               v.ID,
+		ShortDescription: SARIFMessage{Text: description},
+		HelpURI:          vuln.URL(v.ID),
+		Properties:       properties,
+		DefaultConfig:    &SARIFRuleConfig{Level: SARIFLevel(v.Severity)},
+	}
+}
+
+func sarifResult(target TargetAPK, f Finding) SARIFResult {
+	text := fmt.Sprintf("%s %s (%s) is affected by %s", f.Package.Name, f.Package.Version, f.Package.Type, f.Vulnerability.ID)
+	if f.Vulnerability.FixedVersion != "" {
+		text += fmt.Sprintf(", fixed in %s", f.Vulnerability.FixedVersion)
+	}
+
+	var locations []SARIFLocation
+	for _, loc := range strings.Split(f.Package.Location, ", ") {
+		if loc == "" {
+			continue
+		}
+
+		locations = append(locations, SARIFLocation{
+			PhysicalLocation: SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{
+					URI: strings.TrimPrefix(loc, "/"),
+				},
+			},
+		})
+	}
+
+	properties := map[string]any{
+		"package":        f.Package.Name,
+		"packageVersion": f.Package.Version,
+		"packageType":    f.Package.Type,
+	}
+	if f.APK != nil {
+		// For image scans, the finding belongs to the APK that owns the package.
+		target = *f.APK
+	}
+	if target.Name != "" {
+		properties["apk"] = fmt.Sprintf("%s-%s", target.Name, target.Version)
+	}
+
+	return SARIFResult{
+		RuleID:     f.Vulnerability.ID,
+		Level:      SARIFLevel(f.Vulnerability.Severity),
+		Message:    SARIFMessage{Text: text},
+		Locations:  locations,
+		Properties: properties,
+	}
+}
@@ -0,0 +1,108 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+func TestToSARIF(t *testing.T) {
+	finding := &Finding{
+		Package: Package{
+			ID:       "abc123",
+			Name:     "github.com/sirupsen/logrus",
+			Version:  "v1.9.0",
+			Type:     "go-module",
+			Location: "/usr/bin/ko, /usr/bin/other",
+		},
+		Vulnerability: Vulnerability{
+			ID:           "GHSA-2h5h-59f5-c5x9",
+			Severity:     "High",
+			Aliases:      []string{"CVE-2023-12345"},
+			FixedVersion: "1.9.1",
+			CVSS: []vuln.CVSS{
+				{Version: "3.1", BaseScore: 7.5},
+			},
+		},
+	}
+
+	suppressed := SuppressedFinding{
+		Finding: Finding{
+			Package: Package{
+				Name:     "ko",
+				Version:  "0.13.0-r2",
+				Type:     "apk",
+				Location: "/lib/apk/db/installed",
+			},
+			Vulnerability: Vulnerability{
+				ID:       "CVE-2000-22222",
+				Severity: "Low",
+			},
+		},
+		AdvisoryID:    "CVE-2000-22222",
+		Justification: "false positive (component-vulnerability-mismatch)",
+	}
+
+	log := ToSARIF([]SARIFInput{
+		{
+			Result: &Result{
+				TargetAPK: TargetAPK{Name: "ko", Version: "0.13.0-r2"},
+				Findings:  []*Finding{finding},
+			},
+			Suppressed: []SuppressedFinding{suppressed},
+		},
+	})
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+
+	assert.Equal(t, "wolfictl", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "CVE-2000-22222", run.Tool.Driver.Rules[0].ID)
+	rule := run.Tool.Driver.Rules[1]
+	assert.Equal(t, "GHSA-2h5h-59f5-c5x9", rule.ID)
+	assert.Equal(t, "https://github.com/advisories/GHSA-2h5h-59f5-c5x9", rule.HelpURI)
+	assert.Equal(t, "7.5", rule.Properties["security-severity"])
+	assert.Equal(t, SARIFLevelError, rule.DefaultConfig.Level)
+
+	require.Len(t, run.Results, 2)
+
+	result := run.Results[0]
+	assert.Equal(t, "GHSA-2h5h-59f5-c5x9", result.RuleID)
+	assert.Equal(t, SARIFLevelError, result.Level)
+	assert.Equal(t, "github.com/sirupsen/logrus v1.9.0 (go-module) is affected by GHSA-2h5h-59f5-c5x9, fixed in 1.9.1", result.Message.Text)
+	require.Len(t, result.Locations, 2)
+	assert.Equal(t, "usr/bin/ko", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "usr/bin/other", result.Locations[1].PhysicalLocation.ArtifactLocation.URI)
+	assert.Empty(t, result.Suppressions)
+
+	result = run.Results[1]
+	assert.Equal(t, SARIFLevelNote, result.Level)
+	require.Len(t, result.Suppressions, 1)
+	assert.Equal(t, SARIFSuppression{
+		Kind:          "external",
+		Status:        "accepted",
+		Justification: "CVE-2000-22222: false positive (component-vulnerability-mismatch)",
+	}, result.Suppressions[0])
+}
+
+func TestSARIFLevel(t *testing.T) {
+	cases := map[string]string{
+		"Critical":   SARIFLevelError,
+		"High":       SARIFLevelError,
+		"Medium":     SARIFLevelWarning,
+		"Low":        SARIFLevelNote,
+		"Negligible": SARIFLevelNote,
+		"Unknown":    SARIFLevelWarning,
+		"":           SARIFLevelWarning,
+	}
+
+	for severity, expected := range cases {
+		t.Run(severity, func(t *testing.T) {
+			assert.Equal(t, expected, SARIFLevel(severity))
+		})
+	}
+}
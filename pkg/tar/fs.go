@@ -0,0 +1,382 @@
+package tar
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLinkDepth is the maximum number of symlinks followed when resolving a
+// path, to guard against link cycles.
+const maxLinkDepth = 40
+
+// maxFSSize is the maximum total size of the files an FS holds in memory.
+// Several APKs can be read at once, e.g. when scanning a repository, so this
+// keeps a few very large APKs (or a gzip bomb) from exhausting memory.
+var maxFSSize int64 = 1 << 30
+
+// ErrTooLarge is returned when an archive's files are too large to hold in an
+// FS.
+var ErrTooLarge = errors.New("archive contents are too large to read into memory")
+
+// FS is a read-only, in-memory filesystem holding the contents of a tar
+// archive. It's built by reading the archive once, so that the archive's
+// contents can be analyzed without extracting them to disk.
+//
+// Paths in the archive are cleaned and rooted at the FS root, and symlinks are
+// resolved within the FS, so no entry can refer to a location outside of it.
+type FS struct {
+	entries map[string]*entry
+}
+
+type entry struct {
+	header   *tar.Header
+	data     []byte
+	children []string
+}
+
+var (
+	_ fs.FS         = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+)
+
+// NewFS reads the gzip-compressed tar stream, such as an APK, into an FS. APKs
+// are made up of several concatenated gzip streams, all of which are read. If
+// the archive's files add up to more than 1 GiB, NewFS returns ErrTooLarge.
+func NewFS(src io.Reader) (*FS, error) {
+	zr, err := gzip.NewReader(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFS(tar.NewReader(zr))
+}
+
+func newFS(tr *tar.Reader) (*FS, error) {
+	fsys := &FS{
+		entries: map[string]*entry{
+			".": {header: &tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0o755}},
+		},
+	}
+
+	var size int64
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := cleanPath(header.Name)
+		if name == "." {
+			continue
+		}
+
+		e := &entry{header: header}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			size += header.Size
+			if size > maxFSSize {
+				return nil, fmt.Errorf("failed to read %s: %w", header.Name, ErrTooLarge)
+			}
+
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+			}
+			e.data = data
+
+		case tar.TypeDir, tar.TypeSymlink, tar.TypeLink:
+			// No contents to read.
+
+		default:
+			// Device files, FIFOs, and the like have no meaningful contents.
+			continue
+		}
+
+		fsys.add(name, e)
+	}
+
+	for _, e := range fsys.entries {
+		sort.Strings(e.children)
+	}
+
+	return fsys, nil
+}
+
+// add records the entry at the given path, creating any missing parent
+// directories. An entry replaces any earlier entry at the same path.
+func (fsys *FS) add(name string, e *entry) {
+	if existing, ok := fsys.entries[name]; ok {
+		if existing.header.Typeflag == tar.TypeDir && e.header.Typeflag == tar.TypeDir {
+			existing.header = e.header
+			return
+		}
+		e.children = existing.children
+	} else {
+		parent := path.Dir(name)
+		if _, ok := fsys.entries[parent]; !ok {
+			fsys.add(parent, &entry{header: &tar.Header{
+				Name:     parent + "/",
+				Typeflag: tar.TypeDir,
+				Mode:     0o755,
+			}})
+		}
+		p := fsys.entries[parent]
+		p.children = append(p.children, path.Base(name))
+	}
+
+	fsys.entries[name] = e
+}
+
+// cleanPath returns the path of the archive entry relative to the FS root, in
+// the form expected by fs.FS.
+func cleanPath(name string) string {
+	p := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if p == "" {
+		return "."
+	}
+
+	return p
+}
+
+// resolve returns the path of the entry at name, following symlinks (including
+// in parent directories) when followLast is true, or only in parent directories
+// otherwise. Hardlinks are resolved to their targets.
+func (fsys *FS) resolve(op, name string, followLast bool) (string, *entry, error) {
+	if !fs.ValidPath(name) {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	current := "."
+	remaining := strings.Split(name, "/")
+	if name == "." {
+		remaining = nil
+	}
+
+	for hops := 0; ; {
+		if len(remaining) == 0 {
+			e, ok := fsys.entries[current]
+			if !ok {
+				return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+			}
+			return current, e, nil
+		}
+
+		next := cleanPath(path.Join(current, remaining[0]))
+		remaining = remaining[1:]
+
+		e, ok := fsys.entries[next]
+		if !ok {
+			return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+
+		switch e.header.Typeflag {
+		case tar.TypeSymlink:
+			if len(remaining) == 0 && !followLast {
+				return next, e, nil
+			}
+
+			hops++
+			if hops > maxLinkDepth {
+				return "", nil, &fs.PathError{Op: op, Path: name, Err: errors.New("too many levels of symbolic links")}
+			}
+
+			// Symlink targets are resolved relative to the symlink's directory,
+			// or to the FS root if they're absolute.
+			target := e.header.Linkname
+			if !path.IsAbs(target) {
+				target = path.Join(path.Dir(next), target)
+			}
+			current = "."
+			if t := cleanPath(target); t != "." {
+				remaining = append(strings.Split(t, "/"), remaining...)
+			}
+
+		case tar.TypeLink:
+			target := cleanPath(e.header.Linkname)
+			t, ok := fsys.entries[target]
+			if !ok || t.header.Typeflag != tar.TypeReg {
+				return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+			}
+			current = next
+			if len(remaining) == 0 {
+				return target, t, nil
+			}
+
+		default:
+			current = next
+		}
+	}
+}
+
+// Open opens the named file, following symlinks.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	p, e, err := fsys.resolve("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file{fsys: fsys, name: p, entry: e, reader: bytes.NewReader(e.data)}, nil
+}
+
+// ReadFile returns the contents of the named file, following symlinks.
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	_, e, err := fsys.resolve("readfile", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if e.header.Typeflag == tar.TypeDir {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+
+	return bytes.Clone(e.data), nil
+}
+
+// ReadDir returns the entries of the named directory, sorted by name. Symlinks
+// in the directory aren't followed.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, e, err := fsys.resolve("readdir", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if e.header.Typeflag != tar.TypeDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(e.children))
+	for _, child := range e.children {
+		info, err := fsys.Lstat(cleanPath(path.Join(p, child)))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	return entries, nil
+}
+
+// Stat returns information about the named file, following symlinks.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	_, e, err := fsys.resolve("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileInfo{name: path.Base(name), entry: e}, nil
+}
+
+// Lstat returns information about the named file, without following a symlink
+// at the end of the path. The returned FileInfo's Sys method returns the file's
+// *tar.Header.
+func (fsys *FS) Lstat(name string) (fs.FileInfo, error) {
+	_, e, err := fsys.resolve("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileInfo{name: path.Base(name), entry: e}, nil
+}
+
+// ReadLink returns the destination of the named symlink.
+func (fsys *FS) ReadLink(name string) (string, error) {
+	_, e, err := fsys.resolve("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if e.header.Typeflag != tar.TypeSymlink {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return e.header.Linkname, nil
+}
+
+type fileInfo struct {
+	name  string
+	entry *entry
+}
+
+func (fi fileInfo) Name() string { return fi.name }
+
+func (fi fileInfo) Size() int64 { return int64(len(fi.entry.data)) }
+
+func (fi fileInfo) Mode() fs.FileMode { return fi.entry.header.FileInfo().Mode() }
+
+func (fi fileInfo) ModTime() time.Time { return fi.entry.header.ModTime }
+
+func (fi fileInfo) IsDir() bool { return fi.entry.header.Typeflag == tar.TypeDir }
+
+func (fi fileInfo) Sys() any { return fi.entry.header }
+
+type file struct {
+	fsys   *FS
+	name   string
+	entry  *entry
+	reader *bytes.Reader
+	offset int
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return fileInfo{name: path.Base(f.name), entry: f.entry}, nil
+}
+
+func (f *file) Read(b []byte) (int, error) {
+	if f.entry.header.Typeflag == tar.TypeDir {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: errors.New("is a directory")}
+	}
+
+	return f.reader.Read(b)
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	return f.reader.Seek(offset, whence)
+}
+
+func (f *file) ReadAt(b []byte, off int64) (int, error) {
+	return f.reader.ReadAt(b, off)
+}
+
+func (f *file) Close() error {
+	return nil
+}
+
+// ReadDir implements fs.ReadDirFile for directories.
+func (f *file) ReadDir(n int) ([]fs.DirEntry, error) {
+	if f.entry.header.Typeflag != tar.TypeDir {
+		return nil, &fs.PathError{Op: "readdir", Path: f.name, Err: errors.New("not a directory")}
+	}
+
+	entries, err := f.fsys.ReadDir(f.name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries = entries[min(f.offset, len(entries)):]
+	if n <= 0 {
+		f.offset += len(entries)
+		return entries, nil
+	}
+
+	if len(entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(entries) {
+		n = len(entries)
+	}
+	f.offset += n
+
+	return entries[:n], nil
+}
@@ -0,0 +1,160 @@
+package tar
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFS(t *testing.T) {
+	apk, err := os.Open(filepath.Join("testdata", "hello-wolfi-2.12-r1.apk"))
+	require.NoError(t, err)
+	defer apk.Close()
+
+	fsys, err := NewFS(apk)
+	require.NoError(t, err)
+
+	// The control files and the package contents are read from all of the APK's
+	// gzip streams.
+	_, err = fs.Stat(fsys, ".PKGINFO")
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	apk2, err := os.Open(filepath.Join("testdata", "hello-wolfi-2.12-r1.apk"))
+	require.NoError(t, err)
+	defer apk2.Close()
+	require.NoError(t, Untar(apk2, dir))
+
+	expected, err := os.ReadFile(filepath.Join(dir, "usr", "bin", "hello"))
+	require.NoError(t, err)
+	actual, err := fs.ReadFile(fsys, "usr/bin/hello")
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	assert.NoError(t, fstest.TestFS(fsys, ".PKGINFO", "usr/bin/hello"))
+}
+
+func TestNewFS_tooLarge(t *testing.T) {
+	original := maxFSSize
+	maxFSSize = 10
+	t.Cleanup(func() { maxFSSize = original })
+
+	headers := []tar.Header{
+		{Name: "a/one", Typeflag: tar.TypeReg, Mode: 0o644},
+		{Name: "a/two", Typeflag: tar.TypeReg, Mode: 0o644},
+	}
+	_, err := NewFS(newTestArchive(t, headers))
+	require.NoError(t, err)
+
+	headers = append(headers, tar.Header{Name: "a/three", Typeflag: tar.TypeReg, Mode: 0o644})
+	_, err = NewFS(newTestArchive(t, headers))
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestFS_links(t *testing.T) {
+	fsys := newTestFS(t, []tar.Header{
+		{Name: "usr/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "usr/lib/libfoo.so.1.2.3", Typeflag: tar.TypeReg, Mode: 0o644},
+		{Name: "usr/lib/libfoo.so.1", Typeflag: tar.TypeSymlink, Linkname: "libfoo.so.1.2.3"},
+		{Name: "usr/lib/libfoo.so", Typeflag: tar.TypeSymlink, Linkname: "/usr/lib/libfoo.so.1"},
+		{Name: "usr/lib64", Typeflag: tar.TypeSymlink, Linkname: "lib"},
+		{Name: "usr/lib/libbar.so", Typeflag: tar.TypeLink, Linkname: "usr/lib/libfoo.so.1.2.3"},
+		{Name: "escape", Typeflag: tar.TypeSymlink, Linkname: "../../../usr/lib/libfoo.so.1.2.3"},
+		{Name: "loop-a", Typeflag: tar.TypeSymlink, Linkname: "loop-b"},
+		{Name: "loop-b", Typeflag: tar.TypeSymlink, Linkname: "loop-a"},
+		{Name: "../../etc/evil", Typeflag: tar.TypeReg, Mode: 0o644},
+	})
+
+	for _, name := range []string{
+		"usr/lib/libfoo.so.1",
+		"usr/lib/libfoo.so",
+		"usr/lib64/libfoo.so.1.2.3",
+		"usr/lib/libbar.so",
+		"escape",
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := fs.ReadFile(fsys, name)
+			require.NoError(t, err)
+			assert.Equal(t, "usr/lib/libfoo.so.1.2.3", string(data))
+		})
+	}
+
+	t.Run("path traversal", func(t *testing.T) {
+		data, err := fs.ReadFile(fsys, "etc/evil")
+		require.NoError(t, err)
+		assert.Equal(t, "../../etc/evil", string(data))
+	})
+
+	t.Run("symlink cycle", func(t *testing.T) {
+		_, err := fs.ReadFile(fsys, "loop-a")
+		assert.Error(t, err)
+	})
+
+	t.Run("lstat", func(t *testing.T) {
+		info, err := fsys.Lstat("usr/lib/libfoo.so")
+		require.NoError(t, err)
+		assert.Equal(t, fs.ModeSymlink, info.Mode().Type())
+
+		target, err := fsys.ReadLink("usr/lib/libfoo.so")
+		require.NoError(t, err)
+		assert.Equal(t, "/usr/lib/libfoo.so.1", target)
+	})
+
+	t.Run("implicit directories", func(t *testing.T) {
+		info, err := fs.Stat(fsys, "usr/lib")
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+
+		entries, err := fs.ReadDir(fsys, "usr/lib")
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		assert.Equal(t, []string{"libbar.so", "libfoo.so", "libfoo.so.1", "libfoo.so.1.2.3"}, names)
+	})
+}
+
+// newTestFS returns an FS with the given entries. Each regular file's contents
+// are its name.
+func newTestFS(t *testing.T, headers []tar.Header) *FS {
+	t.Helper()
+
+	fsys, err := NewFS(newTestArchive(t, headers))
+	require.NoError(t, err)
+
+	return fsys
+}
+
+// newTestArchive returns a gzip-compressed tar stream with the given entries.
+// Each regular file's contents are its name.
+func newTestArchive(t *testing.T, headers []tar.Header) *bytes.Buffer {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(zw)
+	for i := range headers {
+		h := headers[i]
+		var data []byte
+		if h.Typeflag == tar.TypeReg {
+			data = []byte(h.Name)
+			h.Size = int64(len(data))
+		}
+		require.NoError(t, tw.WriteHeader(&h))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	return buf
+}
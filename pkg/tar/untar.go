@@ -17,7 +17,20 @@ func Untar(src io.Reader, dst string) error {
 	if err != nil {
 		return err
 	}
-	tr := tar.NewReader(zr)
+
+	return extract(tar.NewReader(zr), dst)
+}
+
+// UntarUncompressed behaves like Untar, but for a tar stream that isn't
+// gzip-compressed, such as the flattened filesystem of a container image.
+func UntarUncompressed(src io.Reader, dst string) error {
+	return extract(tar.NewReader(src), dst)
+}
+
+func extract(tr *tar.Reader, dst string) error {
+	if err := os.MkdirAll(dst, os.ModePerm); err != nil {
+		return err
+	}
 
 	// uncompress each element
 	for {
@@ -35,6 +48,11 @@ func Untar(src io.Reader, dst string) error {
 			return err
 		}
 
+		// validate against writing outside of dst through an extracted symlink
+		if err := checkWithin(dst, filepath.Dir(target)); err != nil {
+			return fmt.Errorf("%s: %w", header.Name, err)
+		}
+
 		// check the type
 		switch header.Typeflag {
 		// Create directories
@@ -50,7 +68,11 @@ func Untar(src io.Reader, dst string) error {
 			if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
 				return err
 			}
-			fileToWrite, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			// Replace rather than write through an existing link
+			if err := removeLink(target); err != nil {
+				return err
+			}
+			fileToWrite, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode))
 			if err != nil {
 				return err
 			}
@@ -69,11 +91,91 @@ func Untar(src io.Reader, dst string) error {
 			if err := fileToWrite.Close(); err != nil {
 				return fmt.Errorf("failed to close file %s: %w", target, err)
 			}
+		// Create symlinks, as they are. They're only followed when they're
+		// resolved within dst, see checkWithin.
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			if err := removeLink(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		// Create hardlinks to earlier entries
+		case tar.TypeLink:
+			linkTarget, err := sanitizeArchivePath(dst, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := checkWithin(dst, linkTarget); err != nil {
+				return fmt.Errorf("%s: %w", header.Name, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			if err := removeLink(target); err != nil {
+				return err
+			}
+			if err := os.Link(linkTarget, target); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// checkWithin returns an error if the path, or its nearest existing ancestor,
+// resolves to a location outside of dst once any symlinks in it are followed.
+func checkWithin(dst, p string) error {
+	root, err := filepath.EvalSymlinks(dst)
+	if err != nil {
+		return err
+	}
+
+	existing := p
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s resolves to a location outside of %s", p, dst)
+	}
+
+	return nil
+}
+
+// removeLink removes the symlink or hardlinked file at the given path, if there
+// is one, so that it's replaced rather than written through.
+func removeLink(p string) error {
+	fi, err := os.Lstat(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return nil
+	}
+
+	return os.Remove(p)
+}
+
 // From https://github.com/securego/gosec/issues/324
 func sanitizeArchivePath(d, t string) (v string, err error) {
 	// Convert to forward slashes
@@ -1,11 +1,14 @@
 package tar
 
 import (
+	"archive/tar"
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUntar(t *testing.T) {
@@ -21,3 +24,68 @@ func TestUntar(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, extracted)
 }
+
+func TestUntarUncompressed_links(t *testing.T) {
+	dir := t.TempDir()
+
+	err := UntarUncompressed(newTestTar(t, []testEntry{
+		{header: tar.Header{Name: "usr/lib/libfoo.so.1.2.3", Typeflag: tar.TypeReg, Mode: 0o644}, data: "a much longer first version"},
+		{header: tar.Header{Name: "usr/lib/libfoo.so.1.2.3", Typeflag: tar.TypeReg, Mode: 0o644}, data: "libfoo"},
+		{header: tar.Header{Name: "usr/lib/libfoo.so.1", Typeflag: tar.TypeSymlink, Linkname: "libfoo.so.1.2.3"}},
+		{header: tar.Header{Name: "usr/lib/libbar.so", Typeflag: tar.TypeLink, Linkname: "usr/lib/libfoo.so.1.2.3"}},
+		{header: tar.Header{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "usr/lib"}},
+		{header: tar.Header{Name: "lib/libbaz.so", Typeflag: tar.TypeReg, Mode: 0o644}, data: "libbaz"},
+	}), dir)
+	require.NoError(t, err)
+
+	for _, name := range []string{"usr/lib/libfoo.so.1.2.3", "usr/lib/libfoo.so.1", "usr/lib/libbar.so"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, "libfoo", string(data), name)
+	}
+
+	linkname, err := os.Readlink(filepath.Join(dir, "usr/lib/libfoo.so.1"))
+	require.NoError(t, err)
+	assert.Equal(t, "libfoo.so.1.2.3", linkname)
+
+	data, err := os.ReadFile(filepath.Join(dir, "usr/lib/libbaz.so"))
+	require.NoError(t, err)
+	assert.Equal(t, "libbaz", string(data))
+}
+
+func TestUntarUncompressed_symlinkEscape(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+
+	err := UntarUncompressed(newTestTar(t, []testEntry{
+		{header: tar.Header{Name: "escape", Typeflag: tar.TypeSymlink, Linkname: outside}},
+		{header: tar.Header{Name: "escape/evil", Typeflag: tar.TypeReg, Mode: 0o644}, data: "evil"},
+	}), dir)
+	assert.Error(t, err)
+
+	_, err = os.Stat(filepath.Join(outside, "evil"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+type testEntry struct {
+	header tar.Header
+	data   string
+}
+
+// newTestTar returns an uncompressed tar stream with the given entries.
+func newTestTar(t *testing.T, entries []testEntry) *bytes.Buffer {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		h := e.header
+		h.Size = int64(len(e.data))
+		require.NoError(t, tw.WriteHeader(&h))
+		_, err := tw.Write([]byte(e.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	return buf
+}
@@ -0,0 +1,9 @@
+package vuln
+
+// AliasFinder resolves a vulnerability ID to the other IDs that are known to
+// refer to the same vulnerability (e.g. the GHSA and GO IDs for a CVE).
+type AliasFinder interface {
+	// Aliases returns the known aliases for the given vulnerability ID, not
+	// including the ID itself. If no aliases are known, Aliases returns nil.
+	Aliases(id string) []string
+}
@@ -0,0 +1,85 @@
+package exploit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EPSSScore is a vulnerability's score from the Exploit Prediction Scoring
+// System. See https://www.first.org/epss/.
+type EPSSScore struct {
+	// Probability is the estimated probability (from 0 to 1) that the
+	// vulnerability will be exploited in the next 30 days.
+	Probability float64
+
+	// Percentile is the proportion of all scored vulnerabilities with the same or
+	// a lower Probability.
+	Percentile float64
+}
+
+// ParseEPSS parses EPSS scores from the CSV format published by FIRST, and
+// returns them indexed by CVE ID.
+func ParseEPSS(r io.Reader) (map[string]EPSSScore, error) {
+	br := bufio.NewReader(r)
+
+	// The published file starts with a comment line describing the model version
+	// and score date, which isn't valid CSV.
+	if first, err := br.Peek(1); err == nil && first[0] == '#' {
+		if _, err := br.ReadString('\n'); err != nil {
+			return nil, fmt.Errorf("unable to read EPSS comment line: %w", err)
+		}
+	}
+
+	cr := csv.NewReader(br)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read EPSS header: %w", err)
+	}
+
+	cveCol, epssCol, percentileCol := -1, -1, -1
+	for i, name := range header {
+		switch strings.TrimSpace(name) {
+		case "cve":
+			cveCol = i
+		case "epss":
+			epssCol = i
+		case "percentile":
+			percentileCol = i
+		}
+	}
+	if cveCol < 0 || epssCol < 0 || percentileCol < 0 {
+		return nil, fmt.Errorf("EPSS header must include cve, epss, and percentile columns, got %v", header)
+	}
+
+	scores := make(map[string]EPSSScore)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read EPSS record: %w", err)
+		}
+
+		probability, err := strconv.ParseFloat(record[epssCol], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EPSS probability for %s: %w", record[cveCol], err)
+		}
+		percentile, err := strconv.ParseFloat(record[percentileCol], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EPSS percentile for %s: %w", record[cveCol], err)
+		}
+
+		scores[record[cveCol]] = EPSSScore{
+			Probability: probability,
+			Percentile:  percentile,
+		}
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,184 @@
+// Package exploit provides exploitability data for vulnerabilities, sourced
+// from locally downloaded copies of FIRST's Exploit Prediction Scoring System
+// (EPSS) scores and CISA's Known Exploited Vulnerabilities (KEV) catalog.
+//
+// This package never makes network requests. Data files must be downloaded
+// ahead of time, either to an explicit location or to the default locations in
+// the user's XDG cache directory (see DefaultEPSSPath and DefaultKEVPath).
+package exploit
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+var dataDirectory = path.Join(xdg.CacheHome, "wolfictl", "exploit")
+
+var (
+	// DefaultEPSSPath is where EPSS scores are loaded from when no other path is
+	// given. The file can be downloaded from
+	// https://epss.cyentia.com/epss_scores-current.csv.gz.
+	DefaultEPSSPath = path.Join(dataDirectory, "epss_scores-current.csv.gz")
+
+	// DefaultKEVPath is where the KEV catalog is loaded from when no other path is
+	// given. The file can be downloaded from
+	// https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json.
+	DefaultKEVPath = path.Join(dataDirectory, "known_exploited_vulnerabilities.json")
+)
+
+// Exploitability describes how likely a vulnerability is to be exploited, and
+// whether it's known to have been exploited already.
+type Exploitability struct {
+	// EPSS is the vulnerability's EPSS score, if one is known.
+	EPSS *EPSSScore `json:",omitempty"`
+
+	// KEV is the vulnerability's entry in the KEV catalog, if it has one.
+	KEV *KEVEntry `json:",omitempty"`
+}
+
+// KnownExploited returns true if the vulnerability is listed in the KEV
+// catalog.
+func (e *Exploitability) KnownExploited() bool {
+	return e != nil && e.KEV != nil
+}
+
+// EPSSProbability returns the vulnerability's EPSS probability, or 0 if no EPSS
+// score is known.
+func (e *Exploitability) EPSSProbability() float64 {
+	if e == nil || e.EPSS == nil {
+		return 0
+	}
+
+	return e.EPSS.Probability
+}
+
+// Less reports whether e should be considered less urgent than other. Known
+// exploited vulnerabilities are more urgent than all others, followed by
+// higher EPSS probabilities.
+func (e *Exploitability) Less(other *Exploitability) bool {
+	if e.KnownExploited() != other.KnownExploited() {
+		return other.KnownExploited()
+	}
+
+	return e.EPSSProbability() < other.EPSSProbability()
+}
+
+// Data is a combined set of EPSS scores and KEV catalog entries, indexed by
+// CVE ID.
+type Data struct {
+	epss map[string]EPSSScore
+	kev  map[string]KEVEntry
+}
+
+// Load loads exploitability data from the given EPSS and KEV files. If a path
+// is empty, the corresponding default path is used, and it's not an error for
+// the file at the default path to be missing.
+func Load(epssPath, kevPath string) (*Data, error) {
+	d := &Data{}
+
+	epssFile, err := openDataFile(epssPath, DefaultEPSSPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open EPSS data: %w", err)
+	}
+	if epssFile != nil {
+		defer epssFile.Close()
+
+		d.epss, err = ParseEPSS(epssFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load EPSS data: %w", err)
+		}
+	}
+
+	kevFile, err := openDataFile(kevPath, DefaultKEVPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open KEV data: %w", err)
+	}
+	if kevFile != nil {
+		defer kevFile.Close()
+
+		d.kev, err = ParseKEV(kevFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load KEV data: %w", err)
+		}
+	}
+
+	if d.epss == nil && d.kev == nil {
+		return nil, fmt.Errorf("no exploitability data found, download EPSS scores to %q and/or the KEV catalog to %q, or specify their locations explicitly", DefaultEPSSPath, DefaultKEVPath)
+	}
+
+	return d, nil
+}
+
+// Lookup returns the exploitability data for a vulnerability referred to by
+// the given IDs (e.g. a vulnerability's ID and its aliases). If no data is
+// known for any of the IDs, Lookup returns nil.
+func (d *Data) Lookup(ids ...string) *Exploitability {
+	if d == nil {
+		return nil
+	}
+
+	e := &Exploitability{}
+	for _, id := range ids {
+		if score, ok := d.epss[id]; ok && e.EPSS == nil {
+			score := score
+			e.EPSS = &score
+		}
+
+		if entry, ok := d.kev[id]; ok && e.KEV == nil {
+			entry := entry
+			e.KEV = &entry
+		}
+	}
+
+	if e.EPSS == nil && e.KEV == nil {
+		return nil
+	}
+
+	return e
+}
+
+// openDataFile opens the file at p, or at defaultPath if p is empty. Files
+// ending in ".gz" are transparently decompressed. If the file at the default
+// path doesn't exist, openDataFile returns a nil ReadCloser and no error.
+func openDataFile(p, defaultPath string) (io.ReadCloser, error) {
+	usingDefault := p == ""
+	if usingDefault {
+		p = defaultPath
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if usingDefault && errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if !strings.HasSuffix(p, ".gz") {
+		return f, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to decompress %q: %w", p, err)
+	}
+
+	return gzipFile{Reader: gz, file: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	return errors.Join(g.Reader.Close(), g.file.Close())
+}
@@ -0,0 +1,76 @@
+package exploit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("explicit paths", func(t *testing.T) {
+		d, err := Load("testdata/epss_scores.csv.gz", "testdata/known_exploited_vulnerabilities.json")
+		require.NoError(t, err)
+
+		log4shell := d.Lookup("GHSA-jfh8-c2jp-5v3q", "CVE-2021-44228")
+		require.NotNil(t, log4shell)
+		assert.True(t, log4shell.KnownExploited())
+		assert.Equal(t, 0.97565, log4shell.EPSSProbability())
+		assert.Equal(t, "2021-12-10", log4shell.KEV.DateAdded)
+
+		brotli := d.Lookup("CVE-2020-8927")
+		require.NotNil(t, brotli)
+		assert.False(t, brotli.KnownExploited())
+		assert.Equal(t, 0.46731, brotli.EPSS.Percentile)
+
+		assert.Nil(t, d.Lookup("CVE-2000-0001"))
+	})
+
+	t.Run("uncompressed EPSS file only", func(t *testing.T) {
+		useMissingDefaultPaths(t)
+
+		d, err := Load("testdata/epss_scores.csv", "")
+		require.NoError(t, err)
+		assert.Equal(t, 0.00043, d.Lookup("CVE-2023-39323").EPSSProbability())
+		assert.False(t, d.Lookup("CVE-2021-44228").KnownExploited())
+	})
+
+	t.Run("no data at default paths", func(t *testing.T) {
+		useMissingDefaultPaths(t)
+
+		_, err := Load("", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing explicit path", func(t *testing.T) {
+		_, err := Load("testdata/does-not-exist.csv", "")
+		assert.Error(t, err)
+	})
+}
+
+func TestExploitability_Less(t *testing.T) {
+	kev := &Exploitability{KEV: &KEVEntry{CVEID: "CVE-2021-44228"}}
+	high := &Exploitability{EPSS: &EPSSScore{Probability: 0.9}}
+	low := &Exploitability{EPSS: &EPSSScore{Probability: 0.1}}
+	var none *Exploitability
+
+	assert.True(t, high.Less(kev))
+	assert.False(t, kev.Less(high))
+	assert.True(t, low.Less(high))
+	assert.True(t, none.Less(low))
+	assert.False(t, none.Less(none))
+}
+
+func useMissingDefaultPaths(t *testing.T) {
+	t.Helper()
+
+	originalEPSS, originalKEV := DefaultEPSSPath, DefaultKEVPath
+	t.Cleanup(func() {
+		DefaultEPSSPath, DefaultKEVPath = originalEPSS, originalKEV
+	})
+
+	dir := t.TempDir()
+	DefaultEPSSPath = filepath.Join(dir, "epss_scores-current.csv.gz")
+	DefaultKEVPath = filepath.Join(dir, "known_exploited_vulnerabilities.json")
+}
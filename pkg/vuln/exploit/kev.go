@@ -0,0 +1,38 @@
+package exploit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// KEVEntry is a vulnerability's entry in CISA's Known Exploited
+// Vulnerabilities catalog. See https://www.cisa.gov/known-exploited-vulnerabilities-catalog.
+type KEVEntry struct {
+	CVEID                      string `json:"cveID"`
+	VulnerabilityName          string `json:"vulnerabilityName"`
+	DateAdded                  string `json:"dateAdded"`
+	DueDate                    string `json:"dueDate"`
+	KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+}
+
+type kevCatalog struct {
+	CatalogVersion  string     `json:"catalogVersion"`
+	Vulnerabilities []KEVEntry `json:"vulnerabilities"`
+}
+
+// ParseKEV parses the KEV catalog from the JSON format published by CISA, and
+// returns its entries indexed by CVE ID.
+func ParseKEV(r io.Reader) (map[string]KEVEntry, error) {
+	var catalog kevCatalog
+	if err := json.NewDecoder(r).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("unable to decode KEV catalog: %w", err)
+	}
+
+	entries := make(map[string]KEVEntry, len(catalog.Vulnerabilities))
+	for _, entry := range catalog.Vulnerabilities {
+		entries[entry.CVEID] = entry
+	}
+
+	return entries, nil
+}
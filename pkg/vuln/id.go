@@ -3,20 +3,209 @@ package vuln
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var (
+	RegexCVE     = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+	RegexGHSA    = regexp.MustCompile(`^GHSA(-[23456789cfghjmpqrvwx]{4}){3}$`)
+	RegexGO      = regexp.MustCompile(`^GO-\d{4}-\d{4}$`)
+	RegexPYSEC   = regexp.MustCompile(`^PYSEC-\d{4}-\d+$`)
+	RegexRUSTSEC = regexp.MustCompile(`^RUSTSEC-\d{4}-\d{4}$`)
+	RegexALPINE  = regexp.MustCompile(`^ALPINE-(CVE-\d{4}-\d{4,}|\d+)$`)
 )
 
+// A Namespace is a family of vulnerability IDs issued by a single authority,
+// such as CVE IDs or GitHub Security Advisory IDs.
+type Namespace struct {
+	// Name is the short, human-readable name of the namespace (e.g. "CVE").
+	Name string
+
+	// Pattern matches all valid IDs in the namespace.
+	Pattern *regexp.Regexp
+
+	// URLTemplate is a format string for the canonical URL of a vulnerability in
+	// the namespace. It must contain exactly one "%s" verb, which is replaced by
+	// the vulnerability ID. If empty, IDs in the namespace have no known URL.
+	URLTemplate string
+
+	// Preference orders namespaces when picking the primary ID for a
+	// vulnerability from a set of aliases. Namespaces with lower values are
+	// preferred.
+	Preference int
+}
+
+// URL returns the canonical URL for the given ID in the namespace, or an empty
+// string if the namespace has no URL template.
+func (ns Namespace) URL(id string) string {
+	if ns.URLTemplate == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(ns.URLTemplate, id)
+}
+
 var (
-	RegexCVE  = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
-	RegexGHSA = regexp.MustCompile(`^GHSA(-[23456789cfghjmpqrvwx]{4}){3}$`)
-	RegexGO   = regexp.MustCompile(`^GO-\d{4}-\d{4}$`)
+	NamespaceCVE = Namespace{
+		Name:        "CVE",
+		Pattern:     RegexCVE,
+		URLTemplate: "https://nvd.nist.gov/vuln/detail/%s",
+		Preference:  10,
+	}
+	NamespaceGHSA = Namespace{
+		Name:        "GHSA",
+		Pattern:     RegexGHSA,
+		URLTemplate: "https://github.com/advisories/%s",
+		Preference:  20,
+	}
+	NamespaceGO = Namespace{
+		Name:        "GO",
+		Pattern:     RegexGO,
+		URLTemplate: "https://pkg.go.dev/vuln/%s",
+		Preference:  30,
+	}
+	NamespacePYSEC = Namespace{
+		Name:        "PYSEC",
+		Pattern:     RegexPYSEC,
+		URLTemplate: "https://osv.dev/vulnerability/%s",
+		Preference:  40,
+	}
+	NamespaceRUSTSEC = Namespace{
+		Name:        "RUSTSEC",
+		Pattern:     RegexRUSTSEC,
+		URLTemplate: "https://rustsec.org/advisories/%s.html",
+		Preference:  50,
+	}
+	NamespaceALPINE = Namespace{
+		Name:        "ALPINE",
+		Pattern:     RegexALPINE,
+		URLTemplate: "https://osv.dev/vulnerability/%s",
+		Preference:  60,
+	}
 )
 
-// ValidateID returns an error if the given ID is not a valid CVE ID, GHSA ID,
-// or Go vulnerability ID.
+// namespaces is the registry of known vulnerability ID namespaces.
+var namespaces = []Namespace{
+	NamespaceCVE,
+	NamespaceGHSA,
+	NamespaceGO,
+	NamespacePYSEC,
+	NamespaceRUSTSEC,
+	NamespaceALPINE,
+}
+
+// RegisterNamespace adds a namespace to the registry of known vulnerability ID
+// namespaces, so that IDs in the namespace are considered valid. If a namespace
+// with the same name is already registered, it's replaced. RegisterNamespace
+// is not safe for concurrent use, and it's meant to be called during program
+// initialization.
+func RegisterNamespace(ns Namespace) {
+	for i := range namespaces {
+		if namespaces[i].Name == ns.Name {
+			namespaces[i] = ns
+			return
+		}
+	}
+
+	namespaces = append(namespaces, ns)
+}
+
+// Namespaces returns the registered vulnerability ID namespaces, ordered by
+// preference.
+func Namespaces() []Namespace {
+	sorted := make([]Namespace, len(namespaces))
+	copy(sorted, namespaces)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Preference < sorted[j].Preference
+	})
+
+	return sorted
+}
+
+// NamespaceForID returns the registered namespace that the given ID belongs
+// to. If the ID doesn't belong to any registered namespace, the second return
+// value is false.
+func NamespaceForID(id string) (Namespace, bool) {
+	for _, ns := range Namespaces() {
+		if ns.Pattern.MatchString(id) {
+			return ns, true
+		}
+	}
+
+	return Namespace{}, false
+}
+
+// ValidateID returns an error if the given ID doesn't belong to any registered
+// namespace, such as CVE, GHSA, or Go vulnerability IDs.
 func ValidateID(id string) error {
-	if !RegexCVE.MatchString(id) && !RegexGHSA.MatchString(id) && !RegexGO.MatchString(id) {
-		return fmt.Errorf("%q is not a valid CVE ID, GHSA ID, or Go vulnerability ID", id)
+	if _, ok := NamespaceForID(id); !ok {
+		names := lo.Map(Namespaces(), func(ns Namespace, _ int) string {
+			return ns.Name
+		})
+		return fmt.Errorf("%q is not a valid vulnerability ID, must be one of the known ID types [%s]", id, strings.Join(names, ", "))
 	}
 
 	return nil
 }
+
+// URL returns the canonical URL for the given vulnerability ID, or an empty
+// string if no URL is known.
+func URL(id string) string {
+	ns, ok := NamespaceForID(id)
+	if !ok {
+		return ""
+	}
+
+	return ns.URL(id)
+}
+
+// PreferredID returns the ID from the given set of IDs (all referring to the
+// same vulnerability) that belongs to the most preferred namespace. IDs that
+// don't belong to any registered namespace are only returned if no other IDs
+// are given. If multiple IDs belong to the same namespace, the first one is
+// returned.
+func PreferredID(ids ...string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+
+	preferred := ids[0]
+	preferredNS, preferredOK := NamespaceForID(preferred)
+
+	for _, id := range ids[1:] {
+		ns, ok := NamespaceForID(id)
+		if !ok {
+			continue
+		}
+
+		if !preferredOK || ns.Preference < preferredNS.Preference {
+			preferred, preferredNS, preferredOK = id, ns, true
+		}
+	}
+
+	return preferred
+}
+
+// SelectPrimaryID picks the preferred ID from the given ID and its aliases
+// (see PreferredID), and it returns that ID along with the remaining IDs as
+// aliases.
+func SelectPrimaryID(id string, aliases []string) (primary string, remaining []string) {
+	primary = PreferredID(append([]string{id}, aliases...)...)
+	if primary == id {
+		return id, aliases
+	}
+
+	remaining = append(remaining, id)
+	for _, alias := range aliases {
+		if alias != primary {
+			remaining = append(remaining, alias)
+		}
+	}
+	sort.Strings(remaining)
+
+	return primary, remaining
+}
@@ -1,6 +1,9 @@
 package vuln
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+)
 
 func TestValidateID(t *testing.T) {
 	tests := []struct {
@@ -23,6 +26,21 @@ func TestValidateID(t *testing.T) {
 			id:      "GO-2018-9999",
 			wantErr: false,
 		},
+		{
+			name:    "valid PYSEC",
+			id:      "PYSEC-2021-108",
+			wantErr: false,
+		},
+		{
+			name:    "valid RUSTSEC",
+			id:      "RUSTSEC-2023-0044",
+			wantErr: false,
+		},
+		{
+			name:    "valid Alpine",
+			id:      "ALPINE-CVE-2022-28391",
+			wantErr: false,
+		},
 		{
 			name:    "invalid CVE",
 			id:      "CVE-2018-999",
@@ -38,6 +56,11 @@ func TestValidateID(t *testing.T) {
 			id:      "GO-2018-999",
 			wantErr: true,
 		},
+		{
+			name:    "unknown namespace",
+			id:      "FOO-2018-9999",
+			wantErr: true,
+		},
 		{
 			name:    "empty",
 			id:      "",
@@ -53,3 +76,108 @@ func TestValidateID(t *testing.T) {
 		})
 	}
 }
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{
+			id:       "CVE-2018-9999",
+			expected: "https://nvd.nist.gov/vuln/detail/CVE-2018-9999",
+		},
+		{
+			id:       "GHSA-4qj9-c6q9-9j9q",
+			expected: "https://github.com/advisories/GHSA-4qj9-c6q9-9j9q",
+		},
+		{
+			id:       "GO-2018-9999",
+			expected: "https://pkg.go.dev/vuln/GO-2018-9999",
+		},
+		{
+			id:       "RUSTSEC-2023-0044",
+			expected: "https://rustsec.org/advisories/RUSTSEC-2023-0044.html",
+		},
+		{
+			id:       "FOO-2018-9999",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := URL(tt.id); got != tt.expected {
+				t.Errorf("URL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreferredID(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []string
+		expected string
+	}{
+		{
+			name:     "no IDs",
+			ids:      nil,
+			expected: "",
+		},
+		{
+			name:     "CVE preferred over GHSA",
+			ids:      []string{"GHSA-4qj9-c6q9-9j9q", "CVE-2018-9999"},
+			expected: "CVE-2018-9999",
+		},
+		{
+			name:     "GHSA preferred over PYSEC",
+			ids:      []string{"PYSEC-2021-108", "GHSA-4qj9-c6q9-9j9q"},
+			expected: "GHSA-4qj9-c6q9-9j9q",
+		},
+		{
+			name:     "unknown namespace not preferred",
+			ids:      []string{"FOO-2018-9999", "RUSTSEC-2023-0044"},
+			expected: "RUSTSEC-2023-0044",
+		},
+		{
+			name:     "first ID wins within a namespace",
+			ids:      []string{"CVE-2018-9999", "CVE-2018-1111"},
+			expected: "CVE-2018-9999",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreferredID(tt.ids...); got != tt.expected {
+				t.Errorf("PreferredID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterNamespace(t *testing.T) {
+	original := namespaces
+	t.Cleanup(func() {
+		namespaces = original
+	})
+
+	id := "OSV-2020-111"
+	if err := ValidateID(id); err == nil {
+		t.Fatalf("expected %q to be invalid before registering its namespace", id)
+	}
+
+	RegisterNamespace(Namespace{
+		Name:        "OSV",
+		Pattern:     regexp.MustCompile(`^OSV-\d{4}-\d+$`),
+		URLTemplate: "https://osv.dev/vulnerability/%s",
+		Preference:  100,
+	})
+
+	if err := ValidateID(id); err != nil {
+		t.Errorf("ValidateID() error = %v after registering namespace", err)
+	}
+
+	if got := PreferredID(id, "CVE-2018-9999"); got != "CVE-2018-9999" {
+		t.Errorf("PreferredID() = %q, want %q", got, "CVE-2018-9999")
+	}
+}
@@ -1,6 +1,10 @@
 package vuln
 
-import version "github.com/knqyf263/go-apk-version"
+import (
+	"strings"
+
+	version "github.com/knqyf263/go-apk-version"
+)
 
 type Match struct {
 	Package       Package
@@ -16,6 +20,25 @@ type Package struct {
 type Vulnerability struct {
 	ID, URL  string
 	Severity Severity
+
+	// CVSS lists the CVSS assessments published for the vulnerability.
+	CVSS []CVSS
+}
+
+// CVSS is a single CVSS assessment of a vulnerability.
+type CVSS struct {
+	// Version is the CVSS version of the assessment (e.g. "3.1").
+	Version string `json:"Version"`
+
+	// Vector is the CVSS vector string (e.g.
+	// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H").
+	Vector string `json:"Vector"`
+
+	// BaseScore is the CVSS base score, from 0.0 to 10.0.
+	BaseScore float64 `json:"BaseScore"`
+
+	// Source identifies who published the assessment (e.g. "nvd@nist.gov").
+	Source string `json:"Source,omitempty"`
 }
 
 type CPE struct {
@@ -81,9 +104,31 @@ func (vr VersionRange) Includes(otherVersion string) bool {
 type Severity string
 
 const (
-	SeverityUnknown  Severity = "Unknown"
-	SeverityLow      Severity = "Low"
-	SeverityMedium   Severity = "Medium"
-	SeverityHigh     Severity = "High"
-	SeverityCritical Severity = "Critical"
+	SeverityUnknown    Severity = "Unknown"
+	SeverityNegligible Severity = "Negligible"
+	SeverityLow        Severity = "Low"
+	SeverityMedium     Severity = "Medium"
+	SeverityHigh       Severity = "High"
+	SeverityCritical   Severity = "Critical"
 )
+
+// Severities lists the known severity ratings of a vulnerability, ordered from
+// least to most severe. SeverityUnknown isn't a rating, so it's not included.
+var Severities = []Severity{
+	SeverityNegligible,
+	SeverityLow,
+	SeverityMedium,
+	SeverityHigh,
+	SeverityCritical,
+}
+
+// SeveritiesString returns the Severities as a comma-separated list, for use in
+// messages.
+func SeveritiesString() string {
+	names := make([]string, 0, len(Severities))
+	for _, s := range Severities {
+		names = append(names, string(s))
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -214,6 +214,7 @@ func determineVulnMatch(cve *Cve, packageName, requestCPE string) (*vuln.Match,
 						ID:       cve.ID,
 						URL:      fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%s", cve.ID),
 						Severity: getSeverity(*cve),
+						CVSS:     getCVSS(*cve),
 					},
 				}
 
@@ -251,6 +252,41 @@ func getSeverity(cve Cve) vuln.Severity {
 	}
 }
 
+// getCVSS returns the CVSS v3 and v4 assessments for the CVE, with the most
+// recent CVSS versions first.
+func getCVSS(cve Cve) []vuln.CVSS {
+	var assessments []vuln.CVSS
+
+	for _, m := range cve.Metrics.CvssMetricV40 {
+		assessments = append(assessments, vuln.CVSS{
+			Version:   m.CvssData.Version,
+			Vector:    m.CvssData.VectorString,
+			BaseScore: m.CvssData.BaseScore,
+			Source:    m.Source,
+		})
+	}
+
+	for _, m := range cve.Metrics.CvssMetricV31 {
+		assessments = append(assessments, vuln.CVSS{
+			Version:   m.CvssData.Version,
+			Vector:    m.CvssData.VectorString,
+			BaseScore: m.CvssData.BaseScore,
+			Source:    m.Source,
+		})
+	}
+
+	for _, m := range cve.Metrics.CvssMetricV30 {
+		assessments = append(assessments, vuln.CVSS{
+			Version:   m.CvssData.Version,
+			Vector:    m.CvssData.VectorString,
+			BaseScore: m.CvssData.BaseScore,
+			Source:    m.Source,
+		})
+	}
+
+	return assessments
+}
+
 func cpeStringsMatch(requestCPE, responseCPE string) (bool, error) {
 	req, err := wfn.Parse(requestCPE)
 	if err != nil {
@@ -2,6 +2,7 @@ package nvdapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -75,3 +76,23 @@ func TestDetector_VulnerabilitiesForPackages(t *testing.T) {
 func vulnMatchToCVE(vuln vuln.Match, _ int) string {
 	return vuln.Vulnerability.ID
 }
+
+func TestGetCVSS(t *testing.T) {
+	f, err := os.Open("testdata/brotli.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	var resp CVEsResponse
+	require.NoError(t, json.NewDecoder(f).Decode(&resp))
+	require.NotEmpty(t, resp.Vulnerabilities)
+
+	assessments := getCVSS(resp.Vulnerabilities[0].Cve)
+
+	expected := vuln.CVSS{
+		Version:   "3.1",
+		Vector:    "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:L/A:L",
+		BaseScore: 6.5,
+		Source:    "nvd@nist.gov",
+	}
+	assert.Contains(t, assessments, expected)
+}
@@ -113,6 +113,16 @@ type Metrics struct {
 		ExploitabilityScore float64 `json:"exploitabilityScore"`
 		ImpactScore         float64 `json:"impactScore"`
 	} `json:"cvssMetricV31,omitempty"`
+	CvssMetricV40 []struct {
+		Source   string `json:"source"`
+		Type     string `json:"type"`
+		CvssData struct {
+			Version      string  `json:"version"`
+			VectorString string  `json:"vectorString"`
+			BaseScore    float64 `json:"baseScore"`
+			BaseSeverity string  `json:"baseSeverity"`
+		} `json:"cvssData"`
+	} `json:"cvssMetricV40,omitempty"`
 }
 
 type CpeMatch struct {
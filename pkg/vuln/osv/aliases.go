@@ -0,0 +1,130 @@
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+var _ vuln.AliasFinder = (*AliasIndex)(nil)
+
+// AliasIndex is an in-memory index of vulnerability aliases, built from a
+// local dataset of OSV records, such as an export from osv.dev or a clone of
+// the GitHub Advisory Database.
+type AliasIndex struct {
+	edges map[string]map[string]struct{}
+}
+
+// record is the subset of the OSV schema needed for resolving aliases. See
+// https://ossf.github.io/osv-schema/.
+type record struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+}
+
+// NewAliasIndex walks the given filesystem for OSV records (files ending in
+// ".json") and returns an AliasIndex of the aliases they describe. Files that
+// can't be decoded as OSV records are skipped.
+func NewAliasIndex(fsys fs.FS) (*AliasIndex, error) {
+	idx := &AliasIndex{
+		edges: make(map[string]map[string]struct{}),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(p) != ".json" {
+			return nil
+		}
+
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// A dataset can include other JSON files, like a manifest, which
+		// shouldn't keep the rest of the records from being indexed.
+		var r record
+		if err := json.NewDecoder(f).Decode(&r); err != nil {
+			log.Warnf("skipping %q, which isn't an OSV record: %v", p, err)
+			return nil
+		}
+
+		idx.add(r)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to index OSV records: %w", err)
+	}
+
+	return idx, nil
+}
+
+func (idx *AliasIndex) add(r record) {
+	if r.ID == "" {
+		return
+	}
+
+	for _, alias := range r.Aliases {
+		if alias == "" || alias == r.ID {
+			continue
+		}
+
+		idx.link(r.ID, alias)
+		idx.link(alias, r.ID)
+	}
+}
+
+func (idx *AliasIndex) link(from, to string) {
+	if _, ok := idx.edges[from]; !ok {
+		idx.edges[from] = make(map[string]struct{})
+	}
+
+	idx.edges[from][to] = struct{}{}
+}
+
+// Aliases returns all IDs that are transitively aliased to the given ID, sorted
+// alphabetically. For example, if a GHSA record lists a CVE as an alias, and a
+// GO record lists the same CVE, the aliases for the GHSA ID include both the
+// CVE ID and the GO ID.
+func (idx *AliasIndex) Aliases(id string) []string {
+	if idx == nil {
+		return nil
+	}
+
+	seen := map[string]struct{}{id: {}}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for next := range idx.edges[current] {
+			if _, ok := seen[next]; ok {
+				continue
+			}
+
+			seen[next] = struct{}{}
+			queue = append(queue, next)
+		}
+	}
+
+	delete(seen, id)
+	if len(seen) == 0 {
+		return nil
+	}
+
+	aliases := lo.Keys(seen)
+	sort.Strings(aliases)
+
+	return aliases
+}
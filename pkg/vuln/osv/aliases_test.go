@@ -0,0 +1,48 @@
+package osv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasIndex_Aliases(t *testing.T) {
+	idx, err := NewAliasIndex(os.DirFS("testdata/records"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       string
+		expected []string
+	}{
+		{
+			id:       "CVE-2023-39323",
+			expected: []string{"GHSA-2qjp-425j-52j9", "GO-2023-2095"},
+		},
+		{
+			id:       "GHSA-2qjp-425j-52j9",
+			expected: []string{"CVE-2023-39323", "GO-2023-2095"},
+		},
+		{
+			id:       "GO-2023-2095",
+			expected: []string{"CVE-2023-39323", "GHSA-2qjp-425j-52j9"},
+		},
+		{
+			id:       "GO-2023-1234",
+			expected: nil,
+		},
+		{
+			id:       "CVE-2000-0001",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, idx.Aliases(tt.id)); diff != "" {
+				t.Errorf("Aliases() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}